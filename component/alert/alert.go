@@ -60,17 +60,26 @@ func (m Model) ShouldCloseOnEscape() bool {
 
 func (m Model) View() string {
 	style := lipgloss.NewStyle().Width(m.width).MaxWidth(m.width)
-	icon := ""
 	switch m.Type {
 	case AlertTypeInfo:
 		style = style.Foreground(theme.Colors.Info)
-		icon = "  "
 	case AlertTypeWarning:
 		style = style.Foreground(theme.Colors.Primary)
-		icon = "  "
 	case AlertTypeError:
 		style = style.Foreground(theme.Colors.Error)
-		icon = "  "
 	}
-	return style.Render(icon + m.Content + "\n")
+	return style.Render(Icon(m.Type) + m.Content + "\n")
+}
+
+// Icon returns the icon shown in front of a message of the given type
+func Icon(alertType AlertType) string {
+	switch alertType {
+	case AlertTypeInfo:
+		return "  "
+	case AlertTypeWarning:
+		return "  "
+	case AlertTypeError:
+		return "  "
+	}
+	return ""
 }
@@ -1,11 +1,20 @@
 package app
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jquag/ai-mux/component/alert"
 	"github.com/jquag/ai-mux/component/footer"
+	"github.com/jquag/ai-mux/component/messagelog"
 	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/component/toast"
 	"github.com/jquag/ai-mux/component/worklist"
+	"github.com/jquag/ai-mux/keymap"
+	"github.com/jquag/ai-mux/settings"
 	"github.com/jquag/ai-mux/theme"
 )
 
@@ -13,59 +22,90 @@ type Model struct {
 	width         int
 	height        int
 	workListModel *worklist.Model
-	currentModal  modal.Model
+	modals        modal.Stack
 	footerModel   footer.Model
+	toasts        toast.Model
+	messages      []messagelog.Entry
 }
 
 func New() Model {
 	return Model{
 		workListModel: worklist.New(0, 0),
+		modals:        modal.NewStack(),
 		footerModel:   footer.New(),
+		toasts:        toast.New(),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return m.workListModel.Init()
+	return tea.Batch(m.workListModel.Init(), checkHooks)
+}
+
+// checkHooks warns when Claude's hooks can't report events to ai-mux, without them no
+// work item leaves Starting
+func checkHooks() tea.Msg {
+	if err := settings.SelfCheck(); err != nil {
+		return alert.Alert(fmt.Sprintf("Claude hooks can't reach ai-mux, work item statuses won't update.\n\n%v", err), alert.AlertTypeError)()
+	}
+	return nil
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		switch {
+		case key.Matches(msg, keymap.Keys.ForceQuit):
 			return m, tea.Quit
-		case "q":
-			if !m.currentModal.Show {
+		case key.Matches(msg, keymap.Keys.Quit):
+			if m.modals.Empty() {
 				return m, tea.Quit
 			}
+		case key.Matches(msg, keymap.Keys.Messages):
+			if m.modals.Empty() {
+				return m, modal.ShowModal(messagelog.New(m.messages), "Messages")
+			}
 		}
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.WindowSizeMsg:
 		m.width = min(msg.Width, 150)
 		m.height = msg.Height
 		m.updateLayout()
 		return m, nil
 
+	case toast.ShowToastMsg:
+		m.messages = append(m.messages, messagelog.Entry{Time: time.Now(), Text: msg.Text, Type: msg.Type})
+		var cmd tea.Cmd
+		m.toasts, cmd = m.toasts.Update(msg)
+		return m, cmd
+
 	case modal.ShowModalMsg:
-		m.currentModal = modal.New(m.width, m.height, msg.Content, msg.Title, theme.Colors.Border)
-		m.currentModal.Show = true
-		m.workListModel.Overlayed = true
-		m.footerModel = m.footerModel.WithOverlayed(true)
+		if a, ok := msg.Content.(alert.Model); ok {
+			m.messages = append(m.messages, messagelog.Entry{Time: time.Now(), Text: a.Content, Type: a.Type})
+		}
+		m.modals = m.modals.Push(msg.Content, msg.Title, theme.Colors.Border)
+		m.setOverlayed(true)
 		return m, nil
 
 	case modal.CloseMsg:
-		m.currentModal.Show = false
-		m.workListModel.Overlayed = false
-		m.footerModel = m.footerModel.WithOverlayed(false)
+		m.modals = m.modals.Pop()
+		m.setOverlayed(!m.modals.Empty())
+		return m, nil
+
+	case modal.CloseAllMsg:
+		m.modals = m.modals.Clear()
+		m.setOverlayed(false)
 		return m, nil
 
 	}
 
 	// Only send key messages to the active window
 	if _, ok := msg.(tea.KeyMsg); ok {
-		if m.currentModal.Show {
-			newModal, cmd := m.currentModal.Update(msg)
-			m.currentModal = newModal
+		if !m.modals.Empty() {
+			newModals, cmd := m.modals.Update(msg)
+			m.modals = newModals
 			return m, cmd
 		} else {
 			// Let pane handle other key messages
@@ -76,38 +116,80 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	cmds := []tea.Cmd{}
 
-	if m.currentModal.Show {
-		newModal, cmd := m.currentModal.Update(msg)
-		m.currentModal = newModal
-		cmds = append(cmds, cmd)
-	}
+	newToasts, cmd := m.toasts.Update(msg)
+	m.toasts = newToasts
+	cmds = append(cmds, cmd)
 
-	_, cmd := m.workListModel.Update(msg)
+	newModals, cmd := m.modals.Update(msg)
+	m.modals = newModals
+	cmds = append(cmds, cmd)
+
+	_, cmd = m.workListModel.Update(msg)
 	cmds = append(cmds, cmd)
 
 	return m, tea.Batch(cmds...)
 }
 
+// handleMouse routes mouse events to the top modal, the footer or the work list
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if !m.modals.Empty() {
+		newModals, cmd := m.modals.Update(msg)
+		m.modals = newModals
+		return m, cmd
+	}
+
+	// Everything is padded one column from the left edge
+	msg.X -= 1
+
+	if msg.Y >= m.height-footerHeight {
+		if msg.Y == m.height-1 && msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			if key, ok := m.footer().KeyAt(msg.X); ok {
+				return m, footer.PressKey(key)
+			}
+		}
+		return m, nil
+	}
+
+	_, cmd := m.workListModel.Update(msg)
+	return m, cmd
+}
+
 func (m Model) View() string {
 	style := lipgloss.NewStyle().Padding(0, 1)
 
 	listView := style.Render(m.workListModel.View())
-	footerView := style.Render(m.footerModel.View())
+	footerView := style.Render(m.footer().View())
 	v := lipgloss.JoinVertical(lipgloss.Left, listView, footerView)
-	if m.currentModal.Show {
-		m.currentModal.BackgroundView = v
-		return m.currentModal.View()
-	} else {
-		return v
+	v = m.modals.View(v)
+	if !m.toasts.Empty() {
+		toastsView := m.toasts.View()
+		v = modal.Overlay(v, toastsView, max(0, m.width-lipgloss.Width(toastsView)-1), 1)
 	}
+	return v
+}
+
+// footer returns the footer with hints for the current selection
+func (m Model) footer() footer.Model {
+	return m.footerModel.
+		WithSelected(m.workListModel.Selected()).
+		WithItems(m.workListModel.Items())
 }
 
+// footerHeight is the footer's top border and its line of key hints
+const footerHeight = 2
+
 func (m *Model) updateLayout() {
-	m.workListModel.SetHeight(m.height - 2)
+	m.workListModel.SetHeight(m.height - footerHeight)
 	m.workListModel.SetWidth(m.width - 2)
 
 	m.footerModel = m.footerModel.WithWidth(m.width - 2)
 
-	m.currentModal = m.currentModal.WithWidth(m.width)
-	m.currentModal = m.currentModal.WithHeight(m.height)
+	m.modals = m.modals.WithWidth(m.width)
+	m.modals = m.modals.WithHeight(m.height)
+}
+
+// setOverlayed mutes the main view while any modal is open
+func (m *Model) setOverlayed(overlayed bool) {
+	m.workListModel.Overlayed = overlayed
+	m.footerModel = m.footerModel.WithOverlayed(overlayed)
 }
@@ -0,0 +1,110 @@
+package confirm
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/config"
+	"github.com/jquag/ai-mux/keymap"
+	"github.com/jquag/ai-mux/theme"
+)
+
+// Confirm asks before running onConfirm, listing details of what will be destroyed.
+// When confirmations are turned off in the config onConfirm runs right away.
+func Confirm(title string, message string, details []string, confirmLabel string, onConfirm tea.Cmd) tea.Cmd {
+	if config.Values.SkipConfirmations {
+		return onConfirm
+	}
+	return modal.ShowModal(New(message, details, confirmLabel, onConfirm), title)
+}
+
+type Model struct {
+	Message      string
+	Details      []string
+	confirmLabel string
+	onConfirm    tea.Cmd
+	confirmFocus bool
+	width        int
+}
+
+func New(message string, details []string, confirmLabel string, onConfirm tea.Cmd) Model {
+	return Model{
+		Message:      message,
+		Details:      details,
+		confirmLabel: confirmLabel,
+		onConfirm:    onConfirm,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keymap.Keys.Yes):
+			return m, tea.Sequence(modal.CloseCmd, m.onConfirm)
+		case key.Matches(msg, keymap.Keys.No):
+			return m, modal.CloseCmd
+		case key.Matches(msg, keymap.Keys.SwitchButton):
+			m.confirmFocus = !m.confirmFocus
+		case key.Matches(msg, keymap.Keys.Select):
+			if m.confirmFocus {
+				return m, tea.Sequence(modal.CloseCmd, m.onConfirm)
+			}
+			return m, modal.CloseCmd
+		}
+	}
+	return m, nil
+}
+
+func (m Model) WithWidth(width int) modal.ModalContent {
+	m.width = min(width, 70)
+	return m
+}
+
+func (m Model) WithHeight(height int) modal.ModalContent {
+	return m
+}
+
+func (m Model) ShouldCloseOnEscape() bool {
+	return true
+}
+
+func (m Model) View() string {
+	messageStyle := lipgloss.NewStyle().Width(m.width).Foreground(theme.Colors.Text)
+	detailStyle := lipgloss.NewStyle().Width(m.width).Foreground(theme.Colors.Primary)
+
+	sections := []string{messageStyle.Render(m.Message)}
+	if len(m.Details) > 0 {
+		details := make([]string, len(m.Details))
+		for i, detail := range m.Details {
+			details[i] = "• " + detail
+		}
+		sections = append(sections, "", detailStyle.Render(strings.Join(details, "\n")))
+	}
+	sections = append(sections, "", m.buttonsView(), "")
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m Model) buttonsView() string {
+	button := lipgloss.NewStyle().Padding(0, 1).Foreground(theme.Colors.Muted)
+	focused := button.Inherit(theme.SelectedStyle()).Foreground(theme.Colors.Text).Bold(true)
+
+	cancelLabel := "Cancel (" + keymap.Keys.No.Help().Key + ")"
+	confirmLabel := m.confirmLabel + " (" + keymap.Keys.Yes.Help().Key + ")"
+	cancel := button.Render(cancelLabel)
+	confirm := button.Render(confirmLabel)
+	if m.confirmFocus {
+		confirm = focused.Foreground(theme.Colors.Error).Render(confirmLabel)
+	} else {
+		cancel = focused.Render(cancelLabel)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, cancel, "  ", confirm)
+}
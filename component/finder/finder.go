@@ -0,0 +1,376 @@
+package finder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jquag/ai-mux/component/footer"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/keymap"
+	"github.com/jquag/ai-mux/theme"
+)
+
+// SelectMsg moves the worklist selection to the work item with the id
+type SelectMsg struct {
+	Id string
+}
+
+type mode int
+
+const (
+	modeItems mode = iota
+	modeCommands
+)
+
+const maxRows = 12
+
+// entry is one row that can be searched and chosen
+type entry struct {
+	title   string
+	detail  string
+	hint    string
+	fields  []string // searched in order of importance, the first is the title
+	item    *data.WorkItem
+	binding key.Binding
+}
+
+type result struct {
+	entry     entry
+	positions []int // matched runes of the title
+}
+
+type Model struct {
+	mode    mode
+	input   textinput.Model
+	items   []*data.WorkItem
+	target  *data.WorkItem // item the command palette acts on
+	entries []entry
+	matches []result
+	cursor  int
+	width   int
+	height  int
+}
+
+// New opens the finder searching the work items, starting from the selected one
+func New(items []*data.WorkItem, selected *data.WorkItem) Model {
+	m := Model{items: items, target: selected}
+	m.input = textinput.New()
+	m.input.PromptStyle = lipgloss.NewStyle().Foreground(theme.Colors.Primary)
+	m.input.TextStyle = lipgloss.NewStyle().Foreground(theme.Colors.Text)
+	m.input.PlaceholderStyle = lipgloss.NewStyle().Foreground(theme.Colors.Muted)
+	m.input.Focus()
+	return m.withMode(modeItems)
+}
+
+// NewCommands opens the finder in command palette mode for the selected work item
+func NewCommands(items []*data.WorkItem, selected *data.WorkItem) Model {
+	return New(items, selected).withMode(modeCommands)
+}
+
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(keyMsg, keymap.Keys.FinderUp):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case key.Matches(keyMsg, keymap.Keys.FinderDown):
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+		return m, nil
+	case key.Matches(keyMsg, keymap.Keys.FinderActions):
+		if m.mode == modeCommands {
+			return m.withMode(modeItems), nil
+		}
+		if current, ok := m.current(); ok {
+			m.target = current.entry.item
+			return m.withMode(modeCommands), nil
+		}
+		return m, nil
+	case key.Matches(keyMsg, keymap.Keys.Select):
+		return m, m.choose()
+	}
+
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != before {
+		m.filter()
+	}
+	return m, cmd
+}
+
+// withMode switches between searching items and the actions of the target item
+func (m Model) withMode(mode mode) Model {
+	m.mode = mode
+	m.input.SetValue("")
+	if mode == modeCommands {
+		m.input.Prompt = ": "
+		m.input.Placeholder = "Search actions"
+		m.entries = commandEntries(m.target)
+	} else {
+		m.input.Prompt = "/ "
+		m.input.Placeholder = "Search name, description, tags or branch"
+		m.entries = itemEntries(m.items)
+	}
+	m.filter()
+
+	// Start on the selected item so enter right away keeps the selection
+	for i, match := range m.matches {
+		if mode == modeItems && m.target != nil && match.entry.item != nil && match.entry.item.Id == m.target.Id {
+			m.cursor = i
+		}
+	}
+	return m
+}
+
+// filter ranks the entries against the query, best first, keeping the list order on ties
+func (m *Model) filter() {
+	query := strings.TrimSpace(m.input.Value())
+	m.matches = []result{}
+	scores := []int{}
+	for _, e := range m.entries {
+		best, positions, matched := 0, []int(nil), false
+		for i, field := range e.fields {
+			score, fieldPositions, ok := fuzzyMatch(query, field)
+			if !ok {
+				continue
+			}
+			if i == 0 {
+				// Title matches outrank the same match in another field
+				score += 3
+			}
+			if !matched || score > best {
+				best, matched = score, true
+				positions = nil
+				if i == 0 {
+					positions = fieldPositions
+				}
+			}
+		}
+		if matched {
+			m.matches = append(m.matches, result{entry: e, positions: positions})
+			scores = append(scores, best)
+		}
+	}
+
+	order := make([]int, len(m.matches))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+	sorted := make([]result, len(order))
+	for i, index := range order {
+		sorted[i] = m.matches[index]
+	}
+	m.matches = sorted
+	m.cursor = 0
+}
+
+func (m Model) current() (result, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.matches) {
+		return result{}, false
+	}
+	return m.matches[m.cursor], true
+}
+
+// choose jumps to the highlighted item, or selects the target and presses the action's key
+func (m Model) choose() tea.Cmd {
+	current, ok := m.current()
+	if !ok {
+		return nil
+	}
+	if m.mode == modeItems {
+		return tea.Sequence(modal.CloseCmd, selectCmd(current.entry.item))
+	}
+
+	cmds := []tea.Cmd{modal.CloseCmd}
+	if m.target != nil {
+		cmds = append(cmds, selectCmd(m.target))
+	}
+	return tea.Sequence(append(cmds, footer.PressKey(current.entry.binding.Keys()[0]))...)
+}
+
+func selectCmd(item *data.WorkItem) tea.Cmd {
+	return func() tea.Msg {
+		return SelectMsg{Id: item.Id}
+	}
+}
+
+func itemEntries(items []*data.WorkItem) []entry {
+	entries := []entry{}
+	for _, item := range items {
+		tags := ""
+		if len(item.Tags) > 0 {
+			tags = "#" + strings.Join(item.Tags, " #")
+		}
+		entries = append(entries, entry{
+			title:  item.ShortName,
+			detail: firstLine(item.Description),
+			hint:   strings.TrimSpace(tags + " " + item.BranchName),
+			fields: []string{item.ShortName, item.BranchName, strings.Join(item.Tags, " "), item.Description},
+			item:   item,
+		})
+	}
+	return entries
+}
+
+// commandEntries lists the actions that apply to the item in its current state
+func commandEntries(item *data.WorkItem) []entry {
+	k := keymap.Keys
+	bindings := []key.Binding{}
+	switch {
+	case item == nil:
+	case item.IsClosing:
+		bindings = append(bindings, k.Open, k.Details)
+	case !item.IsStarted():
+		bindings = append(bindings, k.Start, k.StartWith, k.Plan, k.Vibe, k.Edit, k.Rename, k.Details, k.Close)
+	case item.IsWaiting():
+		bindings = append(bindings, k.Approve, k.Open, k.Resume, k.Edit, k.Rename, k.Details, k.Close)
+	case item.IsWorking():
+		bindings = append(bindings, k.Open, k.Edit, k.Rename, k.Details, k.Close)
+	default:
+		bindings = append(bindings, k.Open, k.Resume, k.Edit, k.Rename, k.Details, k.Close)
+	}
+	if item != nil {
+		bindings = append(bindings, k.Template)
+	}
+	bindings = append(bindings, k.Add, k.Import)
+
+	entries := []entry{}
+	for _, b := range bindings {
+		description := describe(b)
+		entries = append(entries, entry{
+			title:   b.Help().Desc,
+			detail:  description,
+			hint:    b.Help().Key,
+			fields:  []string{b.Help().Desc, description},
+			binding: b,
+		})
+	}
+	return entries
+}
+
+// describe returns the help modal description of a binding
+func describe(b key.Binding) string {
+	for _, group := range keymap.Keys.Groups() {
+		for _, e := range group.Entries {
+			if e.Binding.Help() == b.Help() {
+				return e.Description
+			}
+		}
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+func (m Model) View() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Colors.Muted)
+
+	sections := []string{}
+	if m.mode == modeCommands {
+		target := "no work item selected"
+		if m.target != nil {
+			target = m.target.ShortName
+		}
+		sections = append(sections, mutedStyle.Render("Actions for "+target))
+	}
+	sections = append(sections, m.input.View(), "")
+
+	if len(m.matches) == 0 {
+		sections = append(sections, mutedStyle.Italic(true).Render("No matches"))
+	}
+	rows := m.rows()
+	offset := max(0, m.cursor-rows+1)
+	for i := offset; i < len(m.matches) && i < offset+rows; i++ {
+		sections = append(sections, m.rowView(m.matches[i], i == m.cursor))
+	}
+
+	k := keymap.Keys
+	help := k.Select.Help().Key + ": jump · " + k.FinderActions.Help().Key + ": actions · " + k.CloseModal.Help().Key + ": close"
+	if m.mode == modeCommands {
+		help = k.Select.Help().Key + ": run · " + k.FinderActions.Help().Key + ": work items · " + k.CloseModal.Help().Key + ": close"
+	}
+	if len(m.matches) > rows {
+		help = fmt.Sprintf("%d/%d · %s", m.cursor+1, len(m.matches), help)
+	}
+	sections = append(sections, "", mutedStyle.Render(help), "")
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m Model) rows() int {
+	return max(1, min(maxRows, m.height-6))
+}
+
+func (m Model) rowView(r result, selected bool) string {
+	bg := lipgloss.NewStyle()
+	if selected {
+		bg = theme.SelectedStyle()
+	}
+	titleStyle := lipgloss.NewStyle().Foreground(theme.Colors.Title).Inherit(bg)
+	matchStyle := lipgloss.NewStyle().Foreground(theme.Colors.Primary).Bold(true).Inherit(bg)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Colors.Muted).Inherit(bg)
+
+	matched := map[int]bool{}
+	for _, p := range r.positions {
+		matched[p] = true
+	}
+	var title strings.Builder
+	for i, ch := range []rune(r.entry.title) {
+		if matched[i] {
+			title.WriteString(matchStyle.Render(string(ch)))
+		} else {
+			title.WriteString(titleStyle.Render(string(ch)))
+		}
+	}
+
+	hint := mutedStyle.Render(ansi.Truncate(r.entry.hint, m.width/3, "…"))
+	left := title.String()
+	if r.entry.detail != "" {
+		left += mutedStyle.Render("  " + r.entry.detail)
+	}
+	available := m.width - lipgloss.Width(hint) - 1
+	left = ansi.Truncate(left, max(0, available), "…")
+
+	gap := max(1, m.width-lipgloss.Width(left)-lipgloss.Width(hint))
+	return left + bg.Render(strings.Repeat(" ", gap)) + hint
+}
+
+func (m Model) WithWidth(width int) modal.ModalContent {
+	m.width = min(width, 90)
+	m.input.Width = m.width - lipgloss.Width(m.input.Prompt) - 1
+	return m
+}
+
+func (m Model) WithHeight(height int) modal.ModalContent {
+	m.height = height
+	return m
+}
+
+func (m Model) ShouldCloseOnEscape() bool {
+	return true
+}
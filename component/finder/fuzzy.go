@@ -0,0 +1,44 @@
+package finder
+
+import (
+	"unicode"
+)
+
+// fuzzyMatch reports whether the runes of pattern appear in text in order, ignoring case.
+// The score rewards matches at the start of words and runs of consecutive runes, and
+// positions are the rune indexes of text that matched.
+func fuzzyMatch(pattern string, text string) (score int, positions []int, ok bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	t := []rune(text)
+	pi := 0
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if unicode.ToLower(t[ti]) != unicode.ToLower(p[pi]) {
+			continue
+		}
+
+		score++
+		if ti == 0 || isWordSeparator(t[ti-1]) {
+			score += 8
+		}
+		if len(positions) > 0 && positions[len(positions)-1] == ti-1 {
+			score += 5
+		}
+		positions = append(positions, ti)
+		pi++
+	}
+	if pi < len(p) {
+		return 0, nil, false
+	}
+
+	// Prefer matches that start early in the text
+	score -= min(positions[0], 10)
+	return score, positions, true
+}
+
+func isWordSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r)
+}
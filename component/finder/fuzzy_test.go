@@ -0,0 +1,113 @@
+package finder
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		text          string
+		wantOk        bool
+		wantScore     int
+		wantPositions []int
+	}{
+		{
+			name:    "empty pattern matches anything",
+			pattern: "",
+			text:    "login form",
+			wantOk:  true,
+		},
+		{
+			name:          "prefix run",
+			pattern:       "log",
+			text:          "login form",
+			wantOk:        true,
+			wantScore:     21,
+			wantPositions: []int{0, 1, 2},
+		},
+		{
+			name:          "inside a word scores lower and pays for starting late",
+			pattern:       "log",
+			text:          "catalog",
+			wantOk:        true,
+			wantScore:     9,
+			wantPositions: []int{4, 5, 6},
+		},
+		{
+			name:          "word starts",
+			pattern:       "lf",
+			text:          "login form",
+			wantOk:        true,
+			wantScore:     18,
+			wantPositions: []int{0, 6},
+		},
+		{
+			name:          "punctuation separates words",
+			pattern:       "f",
+			text:          "fix/flaky",
+			wantOk:        true,
+			wantScore:     9,
+			wantPositions: []int{0},
+		},
+		{
+			name:          "ignores case",
+			pattern:       "LF",
+			text:          "Login Form",
+			wantOk:        true,
+			wantScore:     18,
+			wantPositions: []int{0, 6},
+		},
+		{
+			name:          "positions are rune indexes",
+			pattern:       "éf",
+			text:          "café fix",
+			wantOk:        true,
+			wantScore:     7,
+			wantPositions: []int{3, 5},
+		},
+		{
+			name:          "early start penalty is capped",
+			pattern:       "z",
+			text:          "aaaaaaaaaaaaaaaaaaaaz",
+			wantOk:        true,
+			wantScore:     -9,
+			wantPositions: []int{20},
+		},
+		{
+			name:    "runes out of order",
+			pattern: "gol",
+			text:    "login",
+			wantOk:  false,
+		},
+		{
+			name:    "missing rune",
+			pattern: "logx",
+			text:    "login form",
+			wantOk:  false,
+		},
+		{
+			name:    "pattern longer than text",
+			pattern: "login form",
+			text:    "login",
+			wantOk:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, positions, ok := fuzzyMatch(tt.pattern, tt.text)
+			if ok != tt.wantOk {
+				t.Fatalf("fuzzyMatch(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.wantOk)
+			}
+			if score != tt.wantScore {
+				t.Errorf("fuzzyMatch(%q, %q) score = %d, want %d", tt.pattern, tt.text, score, tt.wantScore)
+			}
+			if !slices.Equal(positions, tt.wantPositions) {
+				t.Errorf("fuzzyMatch(%q, %q) positions = %v, want %v", tt.pattern, tt.text, positions, tt.wantPositions)
+			}
+		})
+	}
+}
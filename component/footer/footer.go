@@ -2,21 +2,29 @@ package footer
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/keymap"
 	"github.com/jquag/ai-mux/theme"
 )
 
+const separator = " | "
+
 type mapping struct {
 	label string
 	key   string
+	press string // key emitted when the mapping is clicked
 }
 
 type Model struct {
-	mappings  []mapping
 	overlayed bool
 	width     int
+	selected  *data.WorkItem
+	items     []*data.WorkItem
 }
 
 func (m Model) Init() tea.Cmd {
@@ -37,22 +45,156 @@ func (m Model) View() string {
 		borderColor = theme.Colors.Muted
 	}
 
+	mappings, truncated := m.visibleMappings()
 	views := []string{}
-	for i, mapping := range m.mappings {
+	for i, mapping := range mappings {
 		v := fmt.Sprintf("%s: %s",
 			lipgloss.NewStyle().Foreground(labelColor).Render(mapping.label),
 			lipgloss.NewStyle().Foreground(keyColor).Render(mapping.key),
 		)
-		if i < len(m.mappings)-1 {
-			v += lipgloss.NewStyle().Foreground(theme.Colors.Muted).Render(" | ")
+		if i < len(mappings)-1 {
+			v += lipgloss.NewStyle().Foreground(theme.Colors.Muted).Render(separator)
 		}
 		views = append(views, v)
 	}
+	if truncated {
+		views = append(views, lipgloss.NewStyle().Foreground(theme.Colors.Muted).Render(" …"))
+	}
+
+	hints := lipgloss.JoinHorizontal(lipgloss.Top, views...)
+	counts := lipgloss.NewStyle().Foreground(theme.Colors.Muted).Render(m.countsView())
+	gap := strings.Repeat(" ", max(1, m.width-lipgloss.Width(hints)-lipgloss.Width(counts)))
+
 	style := lipgloss.NewStyle().
 		Width(m.width).
 		Border(lipgloss.NormalBorder(), true, false, false, false).
 		BorderForeground(borderColor)
-	return style.Render(lipgloss.JoinHorizontal(lipgloss.Top, views...))
+	return style.Render(hints + gap + counts)
+}
+
+// mappings returns the key hints for the selected item's state, most relevant first
+func (m Model) mappings() []key.Binding {
+	k := keymap.Keys
+	item := m.selected
+
+	bindings := []key.Binding{}
+	switch {
+	case item == nil:
+		bindings = append(bindings, k.Add, k.Import)
+	case item.IsClosing:
+		bindings = append(bindings, k.Open)
+	case !item.IsStarted():
+		bindings = append(bindings, k.Start, k.Plan, k.Vibe, k.Edit, k.Details, k.Close)
+	case item.IsWaiting():
+		bindings = append(bindings, k.Approve, k.Open, k.Resume, k.Details, k.Close)
+	case item.IsWorking():
+		bindings = append(bindings, k.Open, k.Details, k.Close)
+	default:
+		bindings = append(bindings, k.Open, k.Resume, k.Details, k.Close)
+	}
+	return append(bindings, k.Help, k.Quit)
+}
+
+// visibleMappings drops the least relevant hints until they fit next to the counts,
+// keeping Help for as long as possible
+func (m Model) visibleMappings() ([]mapping, bool) {
+	all := []mapping{}
+	for _, b := range m.mappings() {
+		all = append(all, mapping{label: b.Help().Desc, key: b.Help().Key, press: b.Keys()[0]})
+	}
+
+	available := m.width - lipgloss.Width(m.countsView()) - 1
+	visible := all
+	truncated := false
+	for len(visible) > 0 && mappingsWidth(visible, len(visible) < len(all)) > available {
+		truncated = true
+		if len(visible) > 2 {
+			// Keep Help and Quit at the end, drop the last contextual hint before them
+			visible = append(append([]mapping{}, visible[:len(visible)-3]...), visible[len(visible)-2:]...)
+		} else {
+			visible = visible[:len(visible)-1]
+		}
+	}
+	return visible, truncated
+}
+
+func mappingsWidth(mappings []mapping, truncated bool) int {
+	width := 0
+	for i, mapping := range mappings {
+		width += lipgloss.Width(fmt.Sprintf("%s: %s", mapping.label, mapping.key))
+		if i < len(mappings)-1 {
+			width += lipgloss.Width(separator)
+		}
+	}
+	if truncated {
+		width += 2
+	}
+	return width
+}
+
+// countsView summarizes the items by state, like "3 working · 2 waiting"
+func (m Model) countsView() string {
+	working, waiting, done := 0, 0, 0
+	for _, item := range m.items {
+		switch {
+		case item.IsWorking():
+			working++
+		case item.IsWaiting():
+			waiting++
+		case item.IsDone():
+			done++
+		}
+	}
+
+	parts := []string{}
+	if working > 0 {
+		parts = append(parts, fmt.Sprintf("%d working", working))
+	}
+	if waiting > 0 {
+		parts = append(parts, fmt.Sprintf("%d waiting", waiting))
+	}
+	if done > 0 {
+		parts = append(parts, fmt.Sprintf("%d done", done))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// KeyAt returns the key of the mapping rendered at column x of the footer
+func (m Model) KeyAt(x int) (string, bool) {
+	mappings, _ := m.visibleMappings()
+	start := 0
+	for _, mapping := range mappings {
+		end := start + lipgloss.Width(fmt.Sprintf("%s: %s", mapping.label, mapping.key))
+		if x >= start && x < end {
+			return mapping.press, true
+		}
+		start = end + lipgloss.Width(separator)
+	}
+	return "", false
+}
+
+// PressKey emits the key press a clicked mapping stands for
+func PressKey(k string) tea.Cmd {
+	return func() tea.Msg {
+		switch k {
+		case "<cr>", "enter":
+			return tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			return tea.KeyMsg{Type: tea.KeyEscape}
+		case "up":
+			return tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			return tea.KeyMsg{Type: tea.KeyDown}
+		case "tab":
+			return tea.KeyMsg{Type: tea.KeyTab}
+		case "space":
+			return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		}
+		if letter, ok := strings.CutPrefix(k, "ctrl+"); ok && len(letter) == 1 && letter[0] >= 'a' && letter[0] <= 'z' {
+			return tea.KeyMsg{Type: tea.KeyCtrlA + tea.KeyType(letter[0]-'a')}
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	}
 }
 
 func (m Model) WithWidth(width int) Model {
@@ -65,22 +207,20 @@ func (m Model) WithOverlayed(overlayed bool) Model {
 	return m
 }
 
-func New() Model {
-	mappings := []mapping{
-		{"Quit", "q"},
-		{"Help", "?"},
-		// {"Add", "a"},
-		// {"Close", "c"},
-		// {"Plan", "p"},
-		// {"Vibe", "v"},
-		// {"Start", "s"},
-		// {"Resume", "r"},
-		// {"Open", "o"},
-		// {"Info", "<cr>"},
-	}
+// WithSelected sets the item whose actions are hinted
+func (m Model) WithSelected(item *data.WorkItem) Model {
+	m.selected = item
+	return m
+}
 
+// WithItems sets the items summarized by state on the right
+func (m Model) WithItems(items []*data.WorkItem) Model {
+	m.items = items
+	return m
+}
+
+func New() Model {
 	return Model{
-		mappings:  mappings,
 		overlayed: false,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/keymap"
 	"github.com/jquag/ai-mux/theme"
 )
 
@@ -49,38 +50,25 @@ func (m *Model) buildContent() string {
 		Width(m.width - 12).
 		Foreground(theme.Colors.Text)
 	
-	// Build key bindings sections
+	// Build key bindings sections from the active key map
 	var sections []string
 	
-	// General section
-	sections = append(sections, headerStyle.Render("General"))
-	sections = append(sections,
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("q"), descStyle.Render("Quit application")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("?"), descStyle.Render("Show this help")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("j/↓"), descStyle.Render("Move selection down")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("k/↑"), descStyle.Render("Move selection up")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("Esc"), descStyle.Render("Close modal/dialog")),
-		"", // Empty line for spacing
-	)
-	
-	// Work items management
-	sections = append(sections, headerStyle.Render("Work Items"))
-	sections = append(sections,
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("a"), descStyle.Render("Add new work item")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("e"), descStyle.Render("Edit work item")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("Enter"), descStyle.Render("Show work item details inlcuding code changes made")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("c"), descStyle.Render("Close work item")),
-		"", // Empty line for spacing
-	)
+	for _, group := range keymap.Keys.Groups() {
+		sections = append(sections, headerStyle.Render(group.Title))
+		for _, entry := range group.Entries {
+			sections = append(sections,
+				lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render(entry.Binding.Help().Key), descStyle.Render(entry.Description)))
+		}
+		sections = append(sections, "") // Empty line for spacing
+	}
 	
-	// Session management
-	sections = append(sections, headerStyle.Render("Session Management"))
+	// Mouse
+	sections = append(sections, headerStyle.Render("Mouse"))
 	sections = append(sections,
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("s"), descStyle.Render("Start session in default mode (manual accept)")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("p"), descStyle.Render("Start session in plan mode")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("v"), descStyle.Render("Start session in vibe/accept-edits mode")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("r"), descStyle.Render("Resume existing session (in case a claude session was interrupted)")),
-		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("o"), descStyle.Render("Open/switch to tmux window")),
+		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("click"), descStyle.Render("Select a work item, or run a key hint in the footer")),
+		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("dbl-click"), descStyle.Render("Show work item details")),
+		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("wheel"), descStyle.Render("Move the selection, or scroll a dialog")),
+		lipgloss.JoinHorizontal(lipgloss.Top, keyStyle.Render("click out"), descStyle.Render("Close the dialog")),
 	)
 	
 	return strings.Join(sections, "\n")
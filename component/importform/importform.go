@@ -0,0 +1,260 @@
+package importform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/google/uuid"
+	"github.com/jquag/ai-mux/component/alert"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/component/workform"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/service"
+	"github.com/jquag/ai-mux/theme"
+	"github.com/jquag/ai-mux/util"
+)
+
+// source is a branch, optionally already checked out in a worktree, that can be adopted
+type source struct {
+	Branch       string
+	WorktreePath string
+}
+
+type Model struct {
+	form      *huh.Form
+	submitted bool
+	width     int
+	height    int
+	order     int
+	sources   int
+	existing  []*data.WorkItem
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	if m.submitted {
+		return m, nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+
+		if m.form.State == huh.StateCompleted {
+			m.submitted = true
+			return m, tea.Batch(cmd, m.submitCmd())
+		}
+	}
+
+	return m, cmd
+}
+
+func (m Model) View() string {
+	return m.form.View()
+}
+
+func (m Model) WithWidth(width int) modal.ModalContent {
+	m.width = width
+	m.form = m.form.WithWidth(m.width)
+	return m
+}
+
+func (m Model) WithHeight(height int) modal.ModalContent {
+	m.height = min(height, 40)
+	return m
+}
+
+func (m Model) ShouldCloseOnEscape() bool {
+	return true
+}
+
+// HasSources reports whether there is anything to import
+func (m Model) HasSources() bool {
+	return m.sources > 0
+}
+
+// New builds the import form listing existing worktrees and local branches that are
+// not already bound to one of the given work items
+func New(order int, existing []*data.WorkItem) (Model, error) {
+	options, err := sourceOptions(existing)
+	if err != nil {
+		return Model{}, err
+	}
+
+	m := Model{
+		order:    order,
+		sources:  len(options),
+		existing: existing,
+	}
+
+	var selected source
+	shortNameValue := ""
+	descriptionValue := ""
+	sessionIdValue := ""
+	confirmValue := true
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[source]().
+				Key("source").
+				Title("Branch or worktree").
+				Options(options...).
+				Value(&selected),
+			huh.NewInput().
+				Key("shortName").
+				Title("Short name").
+				Placeholder("defaults to the branch name").
+				Validate(func(s string) error {
+					// Left empty the branch name is used, it has to make a valid short name too
+					if s == "" {
+						s = selected.Branch
+					}
+					return workform.ValidateShortName(existing, nil)(s)
+				}).
+				Value(&shortNameValue),
+			huh.NewText().
+				Key("description").
+				Title("Description").
+				Validate(workform.ValidateDescription).
+				Value(&descriptionValue),
+			huh.NewInput().
+				Key("sessionId").
+				Title("Claude session id to resume (optional)").
+				Validate(func(s string) error {
+					if s == "" {
+						return nil
+					}
+					if _, err := uuid.Parse(s); err != nil {
+						return fmt.Errorf("not a valid session id")
+					}
+					if _, err := os.Stat(filepath.Join(util.AiMuxDir, s)); err == nil {
+						return fmt.Errorf("session is already tracked by a work item")
+					}
+					return nil
+				}).
+				Value(&sessionIdValue),
+			huh.NewConfirm().
+				Key("done").
+				Value(&confirmValue).
+				Affirmative("Import (s)").
+				Negative("Cancel (c)"),
+		),
+	).WithTheme(theme.Huh()).WithWidth(0).WithHeight(0)
+
+	m.form = form
+	return m, nil
+}
+
+func sourceOptions(existing []*data.WorkItem) ([]huh.Option[source], error) {
+	worktrees, err := util.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	branches, err := util.ListLocalBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	bound := map[string]bool{}
+	for _, item := range existing {
+		bound[item.BranchName] = true
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	cwd = resolvePath(cwd)
+
+	options := []huh.Option[source]{}
+	checkedOut := map[string]bool{}
+	for _, wt := range worktrees {
+		checkedOut[wt.Branch] = true
+		// Skip the main worktree and detached worktrees, neither can be bound to an item
+		if wt.Branch == "" || resolvePath(wt.Path) == cwd || bound[wt.Branch] {
+			continue
+		}
+		options = append(options, huh.NewOption(
+			fmt.Sprintf("%s (worktree %s)", wt.Branch, wt.Path),
+			source{Branch: wt.Branch, WorktreePath: wt.Path},
+		))
+	}
+	for _, branch := range branches {
+		if checkedOut[branch] || bound[branch] {
+			continue
+		}
+		options = append(options, huh.NewOption(branch, source{Branch: branch}))
+	}
+	return options, nil
+}
+
+// resolvePath makes a path absolute and resolves its symlinks so paths to the same folder
+// compare equal, it is returned cleaned when it can't be resolved
+func resolvePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+func (m Model) submitCmd() tea.Cmd {
+	if !m.form.GetBool("done") {
+		return modal.CloseCmd
+	}
+
+	src, _ := m.form.Get("source").(source)
+	workItem := &data.WorkItem{
+		ShortName:    strings.TrimSpace(m.form.GetString("shortName")),
+		Description:  m.form.GetString("description"),
+		Order:        m.order,
+		BranchName:   src.Branch,
+		WorktreePath: src.WorktreePath,
+		// A worktree that was checked out already is the user's, a branch gets one from ai-mux
+		AdoptedWorktree: src.WorktreePath != "",
+	}
+	if workItem.ShortName == "" {
+		workItem.ShortName = src.Branch
+	}
+	// The source may have changed after the short name was validated
+	if err := workform.ValidateShortName(m.existing, nil)(workItem.ShortName); err != nil {
+		return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to import work item: %v", err), alert.AlertTypeError))
+	}
+
+	// The claude hooks report status by session id, so an adopted session becomes the item id
+	sessionId := m.form.GetString("sessionId")
+	if sessionId != "" {
+		workItem.Id = sessionId
+	} else {
+		workItem.Id = uuid.New().String()
+	}
+
+	if err := service.AssignNames(workItem, m.existing); err != nil {
+		return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to import work item: %v", err), alert.AlertTypeError))
+	}
+	if err := util.SaveWorkItem(workItem); err != nil {
+		return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to import work item: %v", err), alert.AlertTypeError))
+	}
+	if sessionId != "" {
+		// Mark the item as started so it can be resumed rather than started from scratch
+		if err := util.WriteStatusLog(workItem.Id, "Imported", util.AiMuxDir); err != nil {
+			return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to import work item: %v", err), alert.AlertTypeError))
+		}
+	}
+
+	newWorkItemCmd := func() tea.Msg {
+		return data.NewWorkItemMsg{
+			WorkItem: workItem,
+		}
+	}
+	return tea.Batch(modal.CloseCmd, newWorkItemCmd)
+}
@@ -0,0 +1,81 @@
+package messagelog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jquag/ai-mux/component/alert"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/component/toast"
+	"github.com/jquag/ai-mux/theme"
+)
+
+// Entry is an alert or toast that was shown
+type Entry struct {
+	Time time.Time
+	Text string
+	Type alert.AlertType
+}
+
+// Model lists past messages, newest first
+type Model struct {
+	entries  []Entry
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func New(entries []Entry) *Model {
+	return &Model{
+		entries:  entries,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	m.viewport.SetContent(m.buildContent())
+	return m.viewport.View()
+}
+
+func (m *Model) buildContent() string {
+	if len(m.entries) == 0 {
+		return lipgloss.NewStyle().Foreground(theme.Colors.Muted).Italic(true).Render("--No messages--")
+	}
+
+	timeStyle := lipgloss.NewStyle().Foreground(theme.Colors.Muted).Width(10)
+	lines := []string{}
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		entry := m.entries[i]
+		text := lipgloss.NewStyle().
+			Foreground(toast.ColorFor(entry.Type)).
+			Width(m.width - 10).
+			Render(alert.Icon(entry.Type) + entry.Text)
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, timeStyle.Render(entry.Time.Format("15:04:05")), text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) ShouldCloseOnEscape() bool {
+	return true
+}
+
+func (m *Model) WithWidth(width int) modal.ModalContent {
+	m.width = width
+	m.viewport.Width = width
+	return m
+}
+
+func (m *Model) WithHeight(height int) modal.ModalContent {
+	m.height = height
+	m.viewport.Height = height - 4
+	return m
+}
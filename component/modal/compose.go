@@ -0,0 +1,185 @@
+package modal
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+const (
+	esc = '\x1b'
+	bel = '\x07'
+
+	sgrReset  = "\x1b[0m"
+	linkClose = "\x1b]8;;\x07"
+)
+
+// cell is one terminal column of a rendered line
+type cell struct {
+	content      string // grapheme cluster shown in the column
+	style        string // SGR sequences in effect, empty for the default style
+	link         string // OSC 8 hyperlink in effect, without the introducer and terminator
+	continuation bool   // right half of a wide character, content is empty
+}
+
+// overlayLine draws fg over bg starting at column x. A wide character cut by either
+// edge of fg is replaced by spaces, and styles and hyperlinks of bg resume after fg.
+func overlayLine(bg string, x int, fg string) string {
+	bgCells := parseLine(bg)
+	fgCells := parseLine(fg)
+	end := x + len(fgCells)
+
+	for len(bgCells) < end {
+		bgCells = append(bgCells, cell{content: " "})
+	}
+	splitWide(bgCells, x)
+	splitWide(bgCells, end)
+
+	copy(bgCells[x:end], fgCells)
+	return renderCells(bgCells)
+}
+
+// splitWide blanks out a wide character whose halves straddle column i
+func splitWide(cells []cell, i int) {
+	if i <= 0 || i >= len(cells) || !cells[i].continuation {
+		return
+	}
+	cells[i-1] = cell{content: " ", style: cells[i-1].style, link: cells[i-1].link}
+	cells[i] = cell{content: " ", style: cells[i].style, link: cells[i].link}
+}
+
+// parseLine splits a line into styled cells, one per display column. Escape sequences
+// other than SGR and OSC 8 are dropped, as are sequences left unterminated.
+func parseLine(s string) []cell {
+	cells := []cell{}
+	style := ""
+	link := ""
+	state := -1
+
+	for len(s) > 0 {
+		if s[0] == esc {
+			seq, rest, ok := cutSequence(s)
+			s = rest
+			state = -1
+			if !ok {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m"):
+				style = applySGR(style, seq)
+			case strings.HasPrefix(seq, "\x1b]8;"):
+				link = parseLink(seq)
+			}
+			continue
+		}
+
+		var cluster string
+		var width int
+		cluster, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+		if width == 0 {
+			// Zero width clusters (controls, stray combining marks) attach to the previous column
+			if len(cells) > 0 && !cells[len(cells)-1].continuation {
+				cells[len(cells)-1].content += cluster
+			}
+			continue
+		}
+		cells = append(cells, cell{content: cluster, style: style, link: link})
+		for i := 1; i < width; i++ {
+			cells = append(cells, cell{style: style, link: link, continuation: true})
+		}
+	}
+	return cells
+}
+
+// cutSequence splits the escape sequence at the start of s from the rest of the string.
+// ok is false when the sequence is unterminated.
+func cutSequence(s string) (seq string, rest string, ok bool) {
+	if len(s) < 2 {
+		return s, "", false
+	}
+
+	switch s[1] {
+	case '[':
+		// CSI: parameter and intermediate bytes up to a final byte in 0x40-0x7e
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return s[:i+1], s[i+1:], true
+			}
+		}
+		return s, "", false
+	case ']':
+		// OSC: terminated by BEL or ST (ESC \)
+		for i := 2; i < len(s); i++ {
+			if s[i] == bel {
+				return s[:i+1], s[i+1:], true
+			}
+			if s[i] == esc && i+1 < len(s) && s[i+1] == '\\' {
+				return s[:i+2], s[i+2:], true
+			}
+		}
+		return s, "", false
+	default:
+		// Two byte escape such as ESC 7 or ESC =
+		return s[:2], s[2:], true
+	}
+}
+
+// applySGR returns the style in effect after seq is applied to style
+func applySGR(style string, seq string) string {
+	params := seq[2 : len(seq)-1]
+	switch {
+	case params == "" || params == "0":
+		return ""
+	case strings.HasPrefix(params, "0;"):
+		return "\x1b[" + params[2:] + "m"
+	default:
+		return style + seq
+	}
+}
+
+// parseLink returns the hyperlink opened by an OSC 8 sequence, empty when it closes one
+func parseLink(seq string) string {
+	body := strings.TrimPrefix(seq, "\x1b]")
+	body = strings.TrimSuffix(strings.TrimSuffix(body, "\x1b\\"), string(bel))
+	parts := strings.SplitN(body, ";", 3)
+	if len(parts) < 3 || parts[2] == "" {
+		return ""
+	}
+	return body
+}
+
+// renderCells emits cells as a string, switching styles and hyperlinks only where they change
+func renderCells(cells []cell) string {
+	var b strings.Builder
+	style := ""
+	link := ""
+	for _, c := range cells {
+		if c.continuation {
+			continue
+		}
+		if c.link != link {
+			if link != "" {
+				b.WriteString(linkClose)
+			}
+			if c.link != "" {
+				b.WriteString("\x1b]" + c.link + string(bel))
+			}
+			link = c.link
+		}
+		if c.style != style {
+			if style != "" {
+				b.WriteString(sgrReset)
+			}
+			b.WriteString(c.style)
+			style = c.style
+		}
+		b.WriteString(c.content)
+	}
+	if link != "" {
+		b.WriteString(linkClose)
+	}
+	if style != "" {
+		b.WriteString(sgrReset)
+	}
+	return b.String()
+}
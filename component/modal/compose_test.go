@@ -0,0 +1,203 @@
+package modal
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestOverlayLine(t *testing.T) {
+	tests := []struct {
+		name string
+		bg   string
+		x    int
+		fg   string
+		want string
+	}{
+		{
+			name: "plain text",
+			bg:   "hello world",
+			x:    2,
+			fg:   "XY",
+			want: "heXYo world",
+		},
+		{
+			name: "background shorter than offset",
+			bg:   "ab",
+			x:    4,
+			fg:   "Z",
+			want: "ab  Z",
+		},
+		{
+			name: "empty background",
+			bg:   "",
+			x:    1,
+			fg:   "Z",
+			want: " Z",
+		},
+		{
+			name: "style resumes after overlay",
+			bg:   "\x1b[31mredred\x1b[0m",
+			x:    2,
+			fg:   "X",
+			want: "\x1b[31mre\x1b[0mX\x1b[31mred\x1b[0m",
+		},
+		{
+			name: "nested styles",
+			bg:   "\x1b[1m\x1b[31mabc\x1b[0m",
+			x:    1,
+			fg:   "X",
+			want: "\x1b[1m\x1b[31ma\x1b[0mX\x1b[1m\x1b[31mc\x1b[0m",
+		},
+		{
+			name: "reset combined with new style",
+			bg:   "\x1b[1ma\x1b[0;32mbc",
+			x:    1,
+			fg:   "X",
+			want: "\x1b[1ma\x1b[0mX\x1b[32mc\x1b[0m",
+		},
+		{
+			name: "256 colors on both layers",
+			bg:   "\x1b[38;5;196mabc\x1b[0m",
+			x:    1,
+			fg:   "\x1b[48;5;21mX\x1b[0m",
+			want: "\x1b[38;5;196ma\x1b[0m\x1b[48;5;21mX\x1b[0m\x1b[38;5;196mc\x1b[0m",
+		},
+		{
+			name: "unterminated style",
+			bg:   "\x1b[32mabc",
+			x:    1,
+			fg:   "X",
+			want: "\x1b[32ma\x1b[0mX\x1b[32mc\x1b[0m",
+		},
+		{
+			name: "unterminated escape sequence",
+			bg:   "ab\x1b[3",
+			x:    0,
+			fg:   "X",
+			want: "Xb",
+		},
+		{
+			name: "CJK aligned",
+			bg:   "日本語",
+			x:    2,
+			fg:   "XY",
+			want: "日XY語",
+		},
+		{
+			name: "CJK cut on both edges",
+			bg:   "日本語",
+			x:    1,
+			fg:   "XY",
+			want: " XY 語",
+		},
+		{
+			name: "styled CJK cut keeps style on the padding",
+			bg:   "\x1b[31m日本\x1b[0m",
+			x:    1,
+			fg:   "X",
+			want: "\x1b[31m \x1b[0mX\x1b[31m本\x1b[0m",
+		},
+		{
+			name: "emoji ZWJ sequence kept whole",
+			bg:   "a👨‍👩‍👧b",
+			x:    3,
+			fg:   "X",
+			want: "a👨‍👩‍👧X",
+		},
+		{
+			name: "emoji ZWJ sequence cut",
+			bg:   "a👨‍👩‍👧b",
+			x:    2,
+			fg:   "X",
+			want: "a Xb",
+		},
+		{
+			name: "flag emoji",
+			bg:   "🇯🇵🇫🇷",
+			x:    2,
+			fg:   "XY",
+			want: "🇯🇵XY",
+		},
+		{
+			name: "wide characters in foreground",
+			bg:   "abcdef",
+			x:    1,
+			fg:   "日本",
+			want: "a日本f",
+		},
+		{
+			name: "hyperlink resumes after overlay",
+			bg:   "\x1b]8;;http://x\x07link\x1b]8;;\x07 after",
+			x:    1,
+			fg:   "X",
+			want: "\x1b]8;;http://x\x07l\x1b]8;;\x07X\x1b]8;;http://x\x07nk\x1b]8;;\x07 after",
+		},
+		{
+			name: "hyperlink terminated by ST",
+			bg:   "\x1b]8;;http://x\x1b\\ab\x1b]8;;\x1b\\",
+			x:    0,
+			fg:   "X",
+			want: "X\x1b]8;;http://x\x07b\x1b]8;;\x07",
+		},
+		{
+			name: "non SGR sequences are dropped",
+			bg:   "a\x1b[2Kb\x1b]0;title\x07c",
+			x:    1,
+			fg:   "X",
+			want: "aXc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := overlayLine(tt.bg, tt.x, tt.fg)
+			if got != tt.want {
+				t.Errorf("overlayLine(%q, %d, %q)\n got: %q\nwant: %q", tt.bg, tt.x, tt.fg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLineWidth(t *testing.T) {
+	tests := []string{
+		"",
+		"plain",
+		"\x1b[1;38;2;255;0;0mtrue color\x1b[0m",
+		"日本語 mixed ascii",
+		"👨‍👩‍👧 family",
+		"é combining",
+		"\x1b]8;;http://example.com\x07link\x1b]8;;\x07",
+	}
+
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			got := len(parseLine(s))
+			want := ansi.StringWidth(s)
+			if got != want {
+				t.Errorf("len(parseLine(%q)) = %d, want %d", s, got, want)
+			}
+		})
+	}
+}
+
+func TestRenderCellsRoundTrip(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{"\x1b[31mred\x1b[0m plain", "\x1b[31mred\x1b[0m plain"},
+		{"\x1b[31mred\x1b[m", "\x1b[31mred\x1b[0m"},
+		{"\x1b[31m\x1b[0mplain", "plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := renderCells(parseLine(tt.in))
+			if got != tt.want {
+				t.Errorf("renderCells(parseLine(%q)) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
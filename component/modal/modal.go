@@ -4,23 +4,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/muesli/reflow/padding"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jquag/ai-mux/keymap"
+	"github.com/jquag/ai-mux/theme"
 )
 
-const Marker = '\x1B'
-
-func isTerminator(c rune) bool {
-	return (c >= 0x40 && c <= 0x5a) || (c >= 0x61 && c <= 0x7a)
-}
-
-type StyledString struct {
-	open    string
-	content string
-	close   string
-}
-
 type ModalContent interface {
 	View() string
 	Update(msg tea.Msg) (ModalContent, tea.Cmd)
@@ -35,18 +26,23 @@ type Model struct {
 	Content        ModalContent
 	BackgroundView string
 	Show           bool
-	BorderColor    lipgloss.Color
+	BorderColor    lipgloss.TerminalColor
 	Title          string
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case tea.KeyEscape.String():
+		if key.Matches(msg, keymap.Keys.CloseModal) && m.Content.ShouldCloseOnEscape() {
+			return m, CloseCmd
+		}
+	case tea.MouseMsg:
+		// Clicking outside closes the modal whenever escape would
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft && !m.Contains(msg.X, msg.Y) {
 			if m.Content.ShouldCloseOnEscape() {
 				return m, CloseCmd
 			}
+			return m, nil
 		}
 	}
 
@@ -63,163 +59,73 @@ func (m Model) View() string {
 	if !m.Show {
 		return m.BackgroundView
 	}
+	return m.overlay(m.BackgroundView, false)
+}
+
+// overlay renders the modal centered over background, muted when another modal is above it
+func (m Model) overlay(background string, dimmed bool) string {
 	if m.width == 0 || m.height == 0 {
-		return m.BackgroundView
+		return background
 	}
 
+	modal, startX, startY := m.box(dimmed)
+	return Overlay(background, modal, startX, startY)
+}
+
+// box renders the bordered modal and the position it is centered at
+func (m Model) box(dimmed bool) (string, int, int) {
 	modalBoxStyle := lipgloss.NewStyle().Padding(1, 1, 0, 1)
+	borderColor := m.borderColor()
+	if dimmed {
+		borderColor = theme.Colors.Muted
+	}
 	if m.Title == "" {
-		modalBoxStyle = modalBoxStyle.Border(lipgloss.NormalBorder(), true).BorderForeground(m.borderColor())
+		modalBoxStyle = modalBoxStyle.Border(lipgloss.NormalBorder(), true).BorderForeground(borderColor)
 	} else {
-		modalBoxStyle = modalBoxStyle.Inherit(titledBorderStyle(m.borderColor(), m.Title, m.width))
+		modalBoxStyle = modalBoxStyle.Inherit(titledBorderStyle(borderColor, m.Title, m.width))
 	}
 
-	modal := modalBoxStyle.Render(m.Content.View())
+	content := m.Content.View()
+	if dimmed {
+		content = lipgloss.NewStyle().Foreground(theme.Colors.Muted).Faint(theme.Colors.Monochrome).Render(ansi.Strip(content))
+	}
+	modal := modalBoxStyle.Render(content)
 
 	modalWidth, modalHeight := lipgloss.Size(modal)
 
 	startY := max(0, (m.height/2)-(modalHeight/2))
 	startX := max(0, (m.width/2)-(modalWidth/2))
-
-	lines := strings.Split(m.BackgroundView, "\n")
-	udpatedLines := []string{}
-	modalLines := strings.Split(modal, "\n")
-
-	for i, line := range lines {
-		if len(modalLines) > i-startY && i >= startY {
-			replaced := replaceChunk(line, startX, modalLines[i-startY])
-			udpatedLines = append(udpatedLines, replaced)
-		} else {
-			udpatedLines = append(udpatedLines, line)
-		}
-	}
-
-	return strings.Join(udpatedLines, "\n")
+	return modal, startX, startY
 }
 
-func (m Model) borderColor() lipgloss.Color {
-	if m.BorderColor != "" {
-		return m.BorderColor
-	}
-	return lipgloss.Color("#00ff00")
+// Contains reports whether the screen position x, y falls on the modal
+func (m Model) Contains(x int, y int) bool {
+	modal, startX, startY := m.box(false)
+	width, height := lipgloss.Size(modal)
+	return x >= startX && x < startX+width && y >= startY && y < startY+height
 }
 
-func replaceChunk(s string, startIndex int, replacement string) string {
-	if s == "" {
-		s = strings.Repeat(" ", startIndex+1)
-	} else {
-		s = padding.String(s, uint(startIndex))
-	}
-
-	spans := parseStyledString(s)
-	replaced := ""
-
-	replacementWidth := lipgloss.Width(replacement)
-	accountedForLength := 0
-	for _, ss := range spans {
-		currentWidth := lipgloss.Width(ss.open + ss.content + ss.close)
-
-		if accountedForLength > startIndex {
-			// past the start of the modal
-			if accountedForLength > startIndex+replacementWidth {
-				// past the end of the modal
-				replaced = replaced + ss.open + ss.content + ss.close
-			} else if accountedForLength+currentWidth > startIndex+replacementWidth {
-				// this span goes past the end of the modal
-				replaced += replacement + ss.open + rightByDisplayWidth(ss.content, startIndex-accountedForLength+replacementWidth) + ss.close
-			}
-		} else if accountedForLength+currentWidth >= startIndex {
-			// this span gets us to the modal
-			preSub := ss.open + leftByDisplayWidth(ss.content, startIndex-accountedForLength) + ss.close
-			replaced += preSub
-			if accountedForLength+currentWidth >= startIndex+replacementWidth {
-				replaced = replaced + replacement + ss.open + string([]rune(ss.content)[startIndex-accountedForLength+replacementWidth:]) + ss.close
-			}
-		} else {
-			// have not made it to the modal yet
-			replaced = replaced + ss.open + ss.content + ss.close
-		}
-		accountedForLength += currentWidth
-	}
-
-	if lipgloss.Width(replaced) <= startIndex {
-		replaced += replacement
-	}
-
-	return replaced
-}
-
-func leftByDisplayWidth(s string, width int) string {
-	currentWidth := 0
-	var result strings.Builder
-	for _, r := range s {
-		rw := lipgloss.Width(string(r))
-		if currentWidth+rw > width {
-			break
-		}
-		result.WriteRune(r)
-		currentWidth += rw
-	}
-	return result.String()
-}
-
-func rightByDisplayWidth(s string, width int) string {
-	currentWidth := 0
-	var result strings.Builder
-	for _, r := range s {
-		rw := lipgloss.Width(string(r))
-		if currentWidth < width {
-			currentWidth += rw
+// Overlay draws fg over background with its top left corner at x, y
+func Overlay(background string, fg string, x int, y int) string {
+	lines := strings.Split(background, "\n")
+	fgLines := strings.Split(fg, "\n")
+	for i, fgLine := range fgLines {
+		if y+i < 0 || y+i >= len(lines) {
 			continue
 		}
-		result.WriteRune(r)
+		lines[y+i] = overlayLine(lines[y+i], x, fgLine)
 	}
-	return result.String()
+	return strings.Join(lines, "\n")
 }
 
-func parseStyledString(s string) []StyledString {
-	spans := []StyledString{}
-
-	current := StyledString{}
-	ansiOpening := false
-	ansiClosing := false
-	for _, c := range s {
-		if c == Marker {
-			if current.open == "" {
-				if current.content != "" {
-					spans = append(spans, current)
-					current = StyledString{}
-				}
-				ansiOpening = true
-				current.open += string(c)
-			} else {
-				ansiClosing = true
-				current.close += string(c)
-			}
-		} else if ansiOpening {
-			current.open += string(c)
-			if isTerminator(c) {
-				ansiOpening = false
-			}
-		} else if ansiClosing {
-			current.close += string(c)
-			if isTerminator(c) {
-				ansiClosing = false
-				spans = append(spans, current)
-				current = StyledString{}
-			}
-		} else {
-			current.content += string(c)
-		}
-	}
-
-	if current.close == "" && current.content != "" {
-		spans = append(spans, current)
+func (m Model) borderColor() lipgloss.TerminalColor {
+	if m.BorderColor != nil {
+		return m.BorderColor
 	}
-
-	return spans
+	return theme.Colors.Border
 }
 
+// CloseMsg closes the top modal of the stack
 type CloseMsg int
 
 const close CloseMsg = 1
@@ -228,6 +134,13 @@ func CloseCmd() tea.Msg {
 	return close
 }
 
+// CloseAllMsg closes every open modal
+type CloseAllMsg struct{}
+
+func CloseAllCmd() tea.Msg {
+	return CloseAllMsg{}
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -242,7 +155,7 @@ func min(a, b int) int {
 	return b
 }
 
-func titledBorderStyle(color lipgloss.Color, title string, width int) lipgloss.Style {
+func titledBorderStyle(color lipgloss.TerminalColor, title string, width int) lipgloss.Style {
 	style := lipgloss.NewStyle()
 	if width <= 0 {
 		return style
@@ -287,7 +200,7 @@ func (m Model) WithHeight(height int) Model {
 	return m
 }
 
-func New(width, height int, content ModalContent, title string, borderColor lipgloss.Color) Model {
+func New(width, height int, content ModalContent, title string, borderColor lipgloss.TerminalColor) Model {
 	m := Model{
 		Content:     content,
 		Show:        false,
@@ -308,6 +221,7 @@ func ShowModal(content ModalContent, title string) func() tea.Msg {
 	}
 }
 
+// ShowModalMsg pushes a modal on top of the stack, any open modals stay underneath
 type ShowModalMsg struct {
 	Content ModalContent
 	Title   string
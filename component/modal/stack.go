@@ -0,0 +1,113 @@
+package modal
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Stack layers modals over the main view. Only the top modal receives key presses,
+// the ones underneath keep their state and are rendered dimmed until they are on top again.
+type Stack struct {
+	width  int
+	height int
+	modals []Model
+}
+
+func NewStack() Stack {
+	return Stack{}
+}
+
+// Push opens a modal on top of the stack
+func (s Stack) Push(content ModalContent, title string, borderColor lipgloss.TerminalColor) Stack {
+	m := New(s.width, s.height, content, title, borderColor)
+	m.Show = true
+	s.modals = append(append([]Model{}, s.modals...), m)
+	return s
+}
+
+// Pop closes the top modal
+func (s Stack) Pop() Stack {
+	if len(s.modals) > 0 {
+		s.modals = s.modals[:len(s.modals)-1]
+	}
+	return s
+}
+
+// Clear closes every modal
+func (s Stack) Clear() Stack {
+	s.modals = nil
+	return s
+}
+
+func (s Stack) Len() int {
+	return len(s.modals)
+}
+
+func (s Stack) Empty() bool {
+	return len(s.modals) == 0
+}
+
+// Top returns the modal that has focus
+func (s Stack) Top() (Model, bool) {
+	if len(s.modals) == 0 {
+		return Model{}, false
+	}
+	return s.modals[len(s.modals)-1], true
+}
+
+// Update sends key presses and mouse events to the top modal only. Other messages go to
+// every modal since they may be results of commands issued by a modal that is now underneath,
+// like the description written in $EDITOR while an alert was raised.
+func (s Stack) Update(msg tea.Msg) (Stack, tea.Cmd) {
+	if len(s.modals) == 0 {
+		return s, nil
+	}
+
+	modals := append([]Model{}, s.modals...)
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		top := len(modals) - 1
+		var cmd tea.Cmd
+		modals[top], cmd = modals[top].Update(msg)
+		s.modals = modals
+		return s, cmd
+	}
+
+	cmds := []tea.Cmd{}
+	for i := range modals {
+		var cmd tea.Cmd
+		modals[i], cmd = modals[i].Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	s.modals = modals
+	return s, tea.Batch(cmds...)
+}
+
+// View renders the modals over background, dimming all but the top one
+func (s Stack) View(background string) string {
+	v := background
+	for i, m := range s.modals {
+		v = m.overlay(v, i < len(s.modals)-1)
+	}
+	return v
+}
+
+func (s Stack) WithWidth(width int) Stack {
+	s.width = width
+	modals := append([]Model{}, s.modals...)
+	for i := range modals {
+		modals[i] = modals[i].WithWidth(width)
+	}
+	s.modals = modals
+	return s
+}
+
+func (s Stack) WithHeight(height int) Stack {
+	s.height = height
+	modals := append([]Model{}, s.modals...)
+	for i := range modals {
+		modals[i] = modals[i].WithHeight(height)
+	}
+	s.modals = modals
+	return s
+}
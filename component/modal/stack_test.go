@@ -0,0 +1,65 @@
+package modal
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recorder is modal content that keeps the messages it receives
+type recorder struct {
+	received []tea.Msg
+}
+
+func (r *recorder) View() string                { return "" }
+func (r *recorder) ShouldCloseOnEscape() bool   { return false }
+func (r *recorder) WithWidth(int) ModalContent  { return r }
+func (r *recorder) WithHeight(int) ModalContent { return r }
+func (r *recorder) Update(msg tea.Msg) (ModalContent, tea.Cmd) {
+	r.received = append(r.received, msg)
+	return r, nil
+}
+
+// resultMsg stands for the result of a command issued by a modal, like a loaded transcript
+type resultMsg struct{}
+
+func TestStackUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        tea.Msg
+		wantBottom bool
+		wantTop    bool
+	}{
+		{
+			name:       "command result reaches the modal underneath",
+			msg:        resultMsg{},
+			wantBottom: true,
+			wantTop:    true,
+		},
+		{
+			name:    "key press goes to the top modal only",
+			msg:     tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")},
+			wantTop: true,
+		},
+		{
+			name:    "mouse event goes to the top modal only",
+			msg:     tea.MouseMsg{X: 0, Y: 0, Action: tea.MouseActionMotion},
+			wantTop: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bottom, top := &recorder{}, &recorder{}
+			s := NewStack().Push(bottom, "Form", nil).Push(top, "Alert", nil)
+
+			s, _ = s.Update(tt.msg)
+			if got := len(bottom.received) == 1; got != tt.wantBottom {
+				t.Errorf("modal underneath received %v = %v, want %v", tt.msg, got, tt.wantBottom)
+			}
+			if got := len(top.received) == 1; got != tt.wantTop {
+				t.Errorf("top modal received %v = %v, want %v", tt.msg, got, tt.wantTop)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package renameform
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/component/workform"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/service"
+	"github.com/jquag/ai-mux/theme"
+)
+
+type Model struct {
+	form       *huh.Form
+	submitted  bool
+	width      int
+	height     int
+	item       *data.WorkItem
+	otherItems []*data.WorkItem
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	if m.submitted {
+		return m, nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+
+		if m.form.State == huh.StateCompleted {
+			m.submitted = true
+			return m, tea.Batch(cmd, m.submitCmd())
+		}
+	}
+
+	return m, cmd
+}
+
+func (m Model) View() string {
+	return m.form.View()
+}
+
+func (m Model) WithWidth(width int) modal.ModalContent {
+	m.width = width
+	m.form = m.form.WithWidth(m.width)
+	return m
+}
+
+func (m Model) WithHeight(height int) modal.ModalContent {
+	m.height = min(height, 40)
+	return m
+}
+
+func (m Model) ShouldCloseOnEscape() bool {
+	return true
+}
+
+// New builds a form renaming the item, its git branch and its tmux window together
+func New(item *data.WorkItem, otherItems []*data.WorkItem) Model {
+	shortNameValue := item.ShortName
+	confirmValue := true
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("shortName").
+				Title("New short name").
+				Description("Branch "+item.BranchName+" and window "+item.WindowName+" are renamed to match").
+				Validate(workform.ValidateShortName(otherItems, item)).
+				Value(&shortNameValue),
+			huh.NewConfirm().
+				Key("done").
+				Value(&confirmValue).
+				Affirmative("Rename (s)").
+				Negative("Cancel (c)"),
+		),
+	).WithTheme(theme.Huh()).WithWidth(0).WithHeight(0)
+
+	return Model{
+		form:       form,
+		item:       item,
+		otherItems: otherItems,
+	}
+}
+
+func (m Model) submitCmd() tea.Cmd {
+	if !m.form.GetBool("done") {
+		return modal.CloseCmd
+	}
+
+	shortName := strings.TrimSpace(m.form.GetString("shortName"))
+	return tea.Batch(modal.CloseCmd, service.RenameWorkItem(m.item, shortName, m.otherItems))
+}
@@ -0,0 +1,210 @@
+package startform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/jquag/ai-mux/component/alert"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/service"
+	"github.com/jquag/ai-mux/theme"
+	"github.com/jquag/ai-mux/util"
+)
+
+// StartMsg asks for the item to be started in mode once its options are saved
+type StartMsg struct {
+	Item *data.WorkItem
+	Mode string
+}
+
+type Model struct {
+	form      *huh.Form
+	submitted bool
+	width     int
+	height    int
+	item      *data.WorkItem
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	if m.submitted {
+		return m, nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+
+		if m.form.State == huh.StateCompleted {
+			m.submitted = true
+			return m, tea.Batch(cmd, m.submitCmd())
+		}
+	}
+
+	return m, cmd
+}
+
+func (m Model) View() string {
+	return m.form.View()
+}
+
+func (m Model) WithWidth(width int) modal.ModalContent {
+	m.width = width
+	m.form = m.form.WithWidth(m.width)
+	return m
+}
+
+func (m Model) WithHeight(height int) modal.ModalContent {
+	m.height = min(height, 40)
+	return m
+}
+
+func (m Model) ShouldCloseOnEscape() bool {
+	return true
+}
+
+// New builds the dialog starting the item with its saved start options, which it keeps
+// as the item's defaults for the next start or resume
+func New(item *data.WorkItem) Model {
+	modeValue := item.StartMode
+	if modeValue == "" {
+		modeValue = "default"
+	}
+	modelValue := item.Options.Model
+	allowedValue := strings.Join(item.Options.AllowedTools, ", ")
+	disallowedValue := strings.Join(item.Options.DisallowedTools, ", ")
+	extraArgsValue := item.Options.ExtraArgs
+	envValue := formatEnv(item.Options.Env)
+	confirmValue := true
+
+	modes := []huh.Option[string]{}
+	for _, mode := range service.PermissionModes {
+		modes = append(modes, huh.NewOption(mode, mode))
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("mode").
+				Title("Permission mode").
+				Description("bypassPermissions runs every tool without asking").
+				Options(modes...).
+				Inline(true).
+				Value(&modeValue),
+			huh.NewInput().
+				Key("model").
+				Title("Model").
+				Placeholder("Claude's default, e.g. opus or sonnet").
+				Value(&modelValue),
+			huh.NewInput().
+				Key("allowedTools").
+				Title("Allowed tools").
+				Description("Comma separated, like Bash(go test:*), Edit").
+				Value(&allowedValue),
+			huh.NewInput().
+				Key("disallowedTools").
+				Title("Denied tools").
+				Description("Comma separated").
+				Value(&disallowedValue),
+			huh.NewInput().
+				Key("extraArgs").
+				Title("Extra arguments").
+				Description("Passed to claude as typed").
+				Value(&extraArgsValue),
+			huh.NewText().
+				Key("env").
+				Title("Environment").
+				Description("One NAME=value per line").
+				Lines(3).
+				Validate(func(s string) error {
+					_, err := ParseEnv(s)
+					return err
+				}).
+				Value(&envValue),
+			huh.NewConfirm().
+				Key("done").
+				Value(&confirmValue).
+				Affirmative("Start (s)").
+				Negative("Cancel (c)"),
+		),
+	).WithTheme(theme.Huh()).WithWidth(0).WithHeight(0)
+
+	return Model{
+		form: form,
+		item: item,
+	}
+}
+
+func (m Model) submitCmd() tea.Cmd {
+	if !m.form.GetBool("done") {
+		return modal.CloseCmd
+	}
+
+	env, err := ParseEnv(m.form.GetString("env"))
+	if err != nil {
+		return tea.Sequence(modal.CloseCmd, alert.Alert(err.Error(), alert.AlertTypeError))
+	}
+	item := m.item
+	item.StartMode = m.form.GetString("mode")
+	item.Options = data.StartOptions{
+		Model:           strings.TrimSpace(m.form.GetString("model")),
+		AllowedTools:    parseList(m.form.GetString("allowedTools")),
+		DisallowedTools: parseList(m.form.GetString("disallowedTools")),
+		ExtraArgs:       strings.TrimSpace(m.form.GetString("extraArgs")),
+		Env:             env,
+	}
+	if err := util.UpdateWorkItem(item); err != nil {
+		return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to save start options: %v", err), alert.AlertTypeError))
+	}
+
+	return tea.Sequence(modal.CloseCmd, func() tea.Msg {
+		return StartMsg{Item: item, Mode: item.StartMode}
+	})
+}
+
+// parseList splits a comma separated list, dropping empty entries
+func parseList(s string) []string {
+	values := []string{}
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// ParseEnv reads environment variables written one NAME=value per line
+func ParseEnv(s string) (map[string]string, error) {
+	env := map[string]string{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not NAME=value", line)
+		}
+		if err := service.ValidateEnvName(name); err != nil {
+			return nil, err
+		}
+		env[name] = value
+	}
+	return env, nil
+}
+
+func formatEnv(env map[string]string) string {
+	lines := []string{}
+	for name, value := range env {
+		lines = append(lines, name+"="+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
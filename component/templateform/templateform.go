@@ -0,0 +1,196 @@
+package templateform
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/jquag/ai-mux/component/alert"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/component/toast"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/templates"
+	"github.com/jquag/ai-mux/theme"
+)
+
+// ChosenMsg carries the prefilled item to open the work item form with
+type ChosenMsg struct {
+	Item *data.WorkItem
+}
+
+// Model picks a template for a new work item and asks for its placeholders, or saves an
+// item as a template
+type Model struct {
+	form      *huh.Form
+	submitted bool
+	width     int
+	height    int
+	order     int
+	templates []templates.Template
+	saving    *data.WorkItem
+
+	// values are bound to the form fields by pointer so they survive the model being copied
+	choice       *int // index in templates, -1 for a blank item
+	placeholders map[string]*string
+	name         *string
+	confirm      *bool
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	if m.submitted {
+		return m, nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+
+		if m.form.State == huh.StateCompleted {
+			if m.saving == nil && m.placeholders == nil && *m.choice >= 0 {
+				// A template was picked, ask for its placeholders before going on
+				if names := m.templates[*m.choice].Placeholders(); len(names) > 0 {
+					m.form = m.placeholderForm(names).WithWidth(m.width)
+					return m, m.form.Init()
+				}
+			}
+			m.submitted = true
+			return m, tea.Batch(cmd, m.submitCmd())
+		}
+	}
+
+	return m, cmd
+}
+
+func (m Model) View() string {
+	return m.form.View()
+}
+
+func (m Model) WithWidth(width int) modal.ModalContent {
+	m.width = width
+	m.form = m.form.WithWidth(m.width)
+	return m
+}
+
+func (m Model) WithHeight(height int) modal.ModalContent {
+	m.height = min(height, 40)
+	return m
+}
+
+func (m Model) ShouldCloseOnEscape() bool {
+	return true
+}
+
+// New builds a picker of the templates to start a work item from, a blank item first
+func New(list []templates.Template, order int) Model {
+	m := Model{
+		order:     order,
+		templates: list,
+		choice:    new(int),
+		confirm:   new(bool),
+	}
+	*m.choice = -1
+
+	options := []huh.Option[int]{huh.NewOption("Blank work item", -1)}
+	for i, t := range list {
+		options = append(options, huh.NewOption(t.Name, i))
+	}
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Key("template").
+				Title("Template").
+				Description("Templates are read from " + templates.Dir).
+				Options(options...).
+				Value(m.choice),
+		),
+	).WithTheme(theme.Huh()).WithWidth(0).WithHeight(0)
+	return m
+}
+
+// NewSave builds a form saving the item's name, description, tags, start mode and layout
+// as a template
+func NewSave(item *data.WorkItem) Model {
+	m := Model{
+		saving:  item,
+		name:    new(string),
+		confirm: new(bool),
+	}
+	*m.name = item.ShortName
+	*m.confirm = true
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("name").
+				Title("Template name").
+				Description("Saved to "+templates.Dir+", edit it there to add {{placeholders}}").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("template name is required")
+					}
+					return templates.CheckName(s)
+				}).
+				Value(m.name),
+			huh.NewConfirm().
+				Key("done").
+				Value(m.confirm).
+				Affirmative("Save (s)").
+				Negative("Cancel (c)"),
+		),
+	).WithTheme(theme.Huh()).WithWidth(0).WithHeight(0)
+	return m
+}
+
+// placeholderForm asks for a value for each placeholder of the picked template
+func (m *Model) placeholderForm(names []string) *huh.Form {
+	m.placeholders = map[string]*string{}
+	*m.confirm = true
+
+	fields := []huh.Field{}
+	for _, name := range names {
+		value := new(string)
+		m.placeholders[name] = value
+		fields = append(fields, huh.NewInput().Key("placeholder:"+name).Title(name).Value(value))
+	}
+	fields = append(fields,
+		huh.NewConfirm().
+			Key("done").
+			Value(m.confirm).
+			Affirmative("Next (s)").
+			Negative("Cancel (c)"),
+	)
+	return huh.NewForm(huh.NewGroup(fields...)).WithTheme(theme.Huh()).WithWidth(0).WithHeight(0)
+}
+
+func (m Model) submitCmd() tea.Cmd {
+	if m.saving != nil {
+		if !*m.confirm {
+			return modal.CloseCmd
+		}
+		name := strings.TrimSpace(*m.name)
+		if err := templates.Save(templates.FromItem(m.saving, name)); err != nil {
+			return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to save template: %v", err), alert.AlertTypeError))
+		}
+		return tea.Sequence(modal.CloseCmd, toast.Toast("Saved template "+name, alert.AlertTypeInfo))
+	}
+
+	item := &data.WorkItem{Order: m.order}
+	if *m.choice >= 0 {
+		if m.placeholders != nil && !*m.confirm {
+			return modal.CloseCmd
+		}
+		values := map[string]string{}
+		for name, value := range m.placeholders {
+			values[name] = strings.TrimSpace(*value)
+		}
+		item = m.templates[*m.choice].Item(values, m.order)
+	}
+	return tea.Sequence(modal.CloseCmd, func() tea.Msg {
+		return ChosenMsg{Item: item}
+	})
+}
@@ -0,0 +1,112 @@
+package toast
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jquag/ai-mux/component/alert"
+	"github.com/jquag/ai-mux/theme"
+)
+
+// Duration is how long a toast stays on screen
+const Duration = 4 * time.Second
+
+const maxVisible = 5
+
+// ShowToastMsg adds a notification that expires on its own and does not take focus
+type ShowToastMsg struct {
+	Text string
+	Type alert.AlertType
+}
+
+// Toast shows a non-blocking notification, use alert.Alert for errors that need acknowledgement
+func Toast(text string, alertType alert.AlertType) tea.Cmd {
+	return func() tea.Msg {
+		return ShowToastMsg{
+			Text: text,
+			Type: alertType,
+		}
+	}
+}
+
+type expireMsg struct {
+	id int
+}
+
+type entry struct {
+	id   int
+	text string
+	kind alert.AlertType
+}
+
+type Model struct {
+	toasts []entry
+	nextId int
+	width  int
+}
+
+func New() Model {
+	return Model{width: 40}
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ShowToastMsg:
+		id := m.nextId
+		m.nextId++
+		m.toasts = append(m.toasts, entry{id: id, text: msg.Text, kind: msg.Type})
+		if len(m.toasts) > maxVisible {
+			m.toasts = m.toasts[len(m.toasts)-maxVisible:]
+		}
+		return m, tea.Tick(Duration, func(time.Time) tea.Msg {
+			return expireMsg{id: id}
+		})
+	case expireMsg:
+		toasts := []entry{}
+		for _, t := range m.toasts {
+			if t.id != msg.id {
+				toasts = append(toasts, t)
+			}
+		}
+		m.toasts = toasts
+	}
+	return m, nil
+}
+
+func (m Model) Empty() bool {
+	return len(m.toasts) == 0
+}
+
+// View renders the toasts stacked, newest at the bottom
+func (m Model) View() string {
+	views := []string{}
+	for _, t := range m.toasts {
+		color := ColorFor(t.kind)
+		views = append(views, lipgloss.NewStyle().
+			Width(m.width).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Foreground(color).
+			Padding(0, 1).
+			Render(alert.Icon(t.kind)+t.text))
+	}
+	return lipgloss.JoinVertical(lipgloss.Right, views...)
+}
+
+func (m Model) WithWidth(width int) Model {
+	m.width = width
+	return m
+}
+
+// ColorFor returns the theme color of a severity
+func ColorFor(alertType alert.AlertType) lipgloss.TerminalColor {
+	switch alertType {
+	case alert.AlertTypeWarning:
+		return theme.Colors.Primary
+	case alert.AlertTypeError:
+		return theme.Colors.Error
+	default:
+		return theme.Colors.Info
+	}
+}
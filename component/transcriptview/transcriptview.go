@@ -0,0 +1,370 @@
+package transcriptview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/keymap"
+	"github.com/jquag/ai-mux/theme"
+	"github.com/jquag/ai-mux/transcript"
+)
+
+// entry is a message of the conversation as shown, with its fold state
+type entry struct {
+	message transcript.Message
+	folded  bool
+}
+
+// loadedMsg carries the messages of the transcript once it is read
+type loadedMsg struct {
+	messages []transcript.Message
+	err      error
+}
+
+// rendered is the built content of the view, kept until the folds, cursor, query or width change
+type rendered struct {
+	cursor         int
+	query          string
+	width          int
+	content        string
+	selectedTop    int
+	selectedBottom int
+}
+
+// Model reads through the conversation of a work item's Claude session. Tool calls,
+// results and thinking start folded, prompts and replies start open.
+type Model struct {
+	item      *data.WorkItem
+	loading   bool
+	entries   []entry
+	err       error
+	rendered  *rendered // nil when it has to be built again
+	cursor    int
+	followed  int // cursor the viewport was last scrolled to, so scrolling by hand sticks
+	viewport  viewport.Model
+	search    textinput.Model
+	searching bool
+	query     string
+	width     int
+	height    int
+}
+
+func New(item *data.WorkItem) *Model {
+	m := &Model{item: item, loading: true, viewport: viewport.New(0, 0), followed: -1}
+	m.search = textinput.New()
+	m.search.Prompt = "/"
+	m.search.PromptStyle = lipgloss.NewStyle().Foreground(theme.Colors.Primary)
+	m.search.TextStyle = lipgloss.NewStyle().Foreground(theme.Colors.Text)
+	return m
+}
+
+// Init reads the transcript, long sessions take a while to parse
+func (m *Model) Init() tea.Cmd {
+	item := m.item
+	return func() tea.Msg {
+		path := transcript.PathFor(item)
+		if path == "" {
+			return loadedMsg{err: fmt.Errorf("no transcript has been reported for this session yet")}
+		}
+		messages, err := transcript.Messages(path)
+		return loadedMsg{messages: messages, err: err}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	if msg, ok := msg.(loadedMsg); ok {
+		m.loading = false
+		m.err = msg.err
+		for _, message := range msg.messages {
+			folded := message.Kind != transcript.KindPrompt && message.Kind != transcript.KindAssistant
+			m.entries = append(m.entries, entry{message: message, folded: folded})
+		}
+		// Start at the end, where the latest work is
+		m.cursor = max(0, len(m.entries)-1)
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	k := keymap.Keys
+	if m.searching {
+		switch {
+		case key.Matches(keyMsg, k.Select):
+			m.searching = false
+			m.search.Blur()
+			m.query = strings.TrimSpace(m.search.Value())
+			m.jump(1, true)
+		case key.Matches(keyMsg, k.CloseModal):
+			m.searching = false
+			m.search.Blur()
+		default:
+			var cmd tea.Cmd
+			m.search, cmd = m.search.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, k.Down):
+		m.cursor = min(len(m.entries)-1, m.cursor+1)
+	case key.Matches(keyMsg, k.Up):
+		m.cursor = max(0, m.cursor-1)
+	case key.Matches(keyMsg, k.TranscriptTop):
+		m.cursor = 0
+	case key.Matches(keyMsg, k.TranscriptBottom):
+		m.cursor = max(0, len(m.entries)-1)
+	case key.Matches(keyMsg, k.TranscriptFold):
+		if m.cursor < len(m.entries) {
+			m.entries[m.cursor].folded = !m.entries[m.cursor].folded
+			m.followed = -1
+			m.rendered = nil
+		}
+	case key.Matches(keyMsg, k.TranscriptOpenAll):
+		m.foldAll(false)
+	case key.Matches(keyMsg, k.TranscriptCloseAll):
+		m.foldAll(true)
+	case key.Matches(keyMsg, k.TranscriptSearch):
+		m.searching = true
+		m.search.SetValue("")
+		return m, m.search.Focus()
+	case key.Matches(keyMsg, k.TranscriptNext):
+		m.jump(1, false)
+	case key.Matches(keyMsg, k.TranscriptPrevious):
+		m.jump(-1, false)
+	case key.Matches(keyMsg, k.PageDown):
+		m.viewport.HalfPageDown()
+		return m, nil
+	case key.Matches(keyMsg, k.PageUp):
+		m.viewport.HalfPageUp()
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m *Model) foldAll(folded bool) {
+	for i := range m.entries {
+		m.entries[i].folded = folded
+	}
+	m.rendered = nil
+}
+
+// jump moves to the next message matching the query in direction dir, wrapping around,
+// and unfolds it. The current message is included when searching anew.
+func (m *Model) jump(dir int, includeCurrent bool) {
+	if m.query == "" || len(m.entries) == 0 {
+		return
+	}
+	start := 1
+	if includeCurrent {
+		start = 0
+	}
+	for step := start; step <= len(m.entries); step++ {
+		i := ((m.cursor+dir*step)%len(m.entries) + len(m.entries)) % len(m.entries)
+		if m.matches(m.entries[i]) {
+			m.cursor = i
+			m.entries[i].folded = false
+			m.followed = -1
+			m.rendered = nil
+			return
+		}
+	}
+}
+
+func (m *Model) matches(e entry) bool {
+	query := strings.ToLower(m.query)
+	return strings.Contains(strings.ToLower(e.message.Text), query) ||
+		strings.Contains(strings.ToLower(header(e.message)), query)
+}
+
+func (m *Model) View() string {
+	if m.loading {
+		return lipgloss.NewStyle().Foreground(theme.Colors.Muted).Italic(true).Render("Loading transcript...")
+	}
+	if m.err != nil {
+		return lipgloss.NewStyle().Foreground(theme.Colors.Error).Width(m.width).Render(m.err.Error())
+	}
+	if len(m.entries) == 0 {
+		return lipgloss.NewStyle().Foreground(theme.Colors.Muted).Italic(true).Render("--Empty transcript--")
+	}
+
+	r := m.rendered
+	if r == nil || r.cursor != m.cursor || r.query != m.query || r.width != m.width {
+		content, selectedTop, selectedBottom := m.buildContent()
+		r = &rendered{cursor: m.cursor, query: m.query, width: m.width,
+			content: content, selectedTop: selectedTop, selectedBottom: selectedBottom}
+		m.rendered = r
+		m.viewport.SetContent(content)
+	}
+
+	// Bring a newly selected message into view, its header first when it is taller than the view
+	if m.cursor != m.followed {
+		if r.selectedBottom > m.viewport.YOffset+m.viewport.Height {
+			m.viewport.SetYOffset(r.selectedBottom - m.viewport.Height)
+		}
+		if r.selectedTop < m.viewport.YOffset {
+			m.viewport.SetYOffset(r.selectedTop)
+		}
+		m.followed = m.cursor
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), m.statusLine())
+}
+
+// buildContent renders every message and the line range of the selected one
+func (m *Model) buildContent() (string, int, int) {
+	lines := []string{}
+	selectedTop, selectedBottom := 0, 0
+	for i, e := range m.entries {
+		if i == m.cursor {
+			selectedTop = len(lines)
+		}
+		lines = append(lines, m.headerView(e, i == m.cursor))
+		if !e.folded {
+			text := m.highlight(e.message.Text, lipgloss.NewStyle().Foreground(bodyColor(e.message)))
+			body := lipgloss.NewStyle().Width(max(1, m.width-2)).MarginLeft(2).Render(text)
+			lines = append(lines, strings.Split(body, "\n")...)
+		}
+		if i == m.cursor {
+			selectedBottom = len(lines)
+		}
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n"), selectedTop, selectedBottom
+}
+
+func (m *Model) headerView(e entry, selected bool) string {
+	style := lipgloss.NewStyle().Foreground(headerColor(e.message)).Bold(true)
+	if selected {
+		style = style.Inherit(theme.SelectedStyle())
+	}
+	arrow := "▾ "
+	if e.folded {
+		arrow = "▸ "
+	}
+
+	text := header(e.message)
+	if e.message.Kind == transcript.KindToolResult || e.message.Kind == transcript.KindThinking {
+		text += fmt.Sprintf(" (%d lines)", strings.Count(strings.TrimRight(e.message.Text, "\n"), "\n")+1)
+	}
+	if !e.message.Time.IsZero() {
+		text += "  " + e.message.Time.Local().Format("15:04:05")
+	}
+	return style.Width(m.width).MaxWidth(m.width).Render(arrow + text)
+}
+
+func header(message transcript.Message) string {
+	switch message.Kind {
+	case transcript.KindPrompt:
+		return "You"
+	case transcript.KindAssistant:
+		return "Claude"
+	case transcript.KindThinking:
+		return "Thinking"
+	case transcript.KindToolCall:
+		if message.Input == "" {
+			return message.Tool
+		}
+		return message.Tool + ": " + message.Input
+	case transcript.KindToolResult:
+		if message.IsError {
+			return "Error"
+		}
+		return "Result"
+	}
+	return ""
+}
+
+func headerColor(message transcript.Message) lipgloss.TerminalColor {
+	switch message.Kind {
+	case transcript.KindPrompt:
+		return theme.Colors.Primary
+	case transcript.KindAssistant:
+		return theme.Colors.Title
+	case transcript.KindToolCall:
+		return theme.Colors.Info
+	case transcript.KindToolResult:
+		if message.IsError {
+			return theme.Colors.Error
+		}
+		return theme.Colors.Success
+	default:
+		return theme.Colors.Muted
+	}
+}
+
+func bodyColor(message transcript.Message) lipgloss.TerminalColor {
+	switch message.Kind {
+	case transcript.KindPrompt, transcript.KindAssistant:
+		return theme.Colors.Text
+	default:
+		return theme.Colors.Muted
+	}
+}
+
+// highlight renders text in style with the occurrences of the search query reversed
+func (m *Model) highlight(text string, style lipgloss.Style) string {
+	lower := strings.ToLower(text)
+	query := strings.ToLower(m.query)
+	// Lowercasing can change byte lengths outside ASCII, skip highlighting then
+	if query == "" || len(lower) != len(text) {
+		return style.Render(text)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 {
+			b.WriteString(style.Render(text))
+			return b.String()
+		}
+		b.WriteString(style.Render(text[:i]))
+		b.WriteString(style.Reverse(true).Render(text[i : i+len(query)]))
+		text, lower = text[i+len(query):], lower[i+len(query):]
+	}
+}
+
+func (m *Model) statusLine() string {
+	if m.searching {
+		return m.search.View()
+	}
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Colors.Muted)
+	k := keymap.Keys
+	help := keymap.Hint(k.Down, k.Up, k.TranscriptFold, k.TranscriptOpenAll, k.TranscriptCloseAll, k.PageDown, k.PageUp, k.TranscriptSearch)
+	if m.query != "" {
+		help = fmt.Sprintf("/%s · %s · %s", m.query, keymap.Hint(k.TranscriptNext, k.TranscriptPrevious), help)
+	}
+	position := fmt.Sprintf("%d/%d  ", m.cursor+1, len(m.entries))
+	return mutedStyle.Render(ansi.Truncate(position+help, m.width, "…"))
+}
+
+func (m *Model) ShouldCloseOnEscape() bool {
+	// Escape ends a search before it closes the viewer
+	return !m.searching
+}
+
+func (m *Model) WithWidth(width int) modal.ModalContent {
+	m.width = width
+	m.viewport.Width = width
+	m.search.Width = width - 2
+	return m
+}
+
+func (m *Model) WithHeight(height int) modal.ModalContent {
+	m.height = height
+	m.viewport.Height = max(1, height-5)
+	return m
+}
@@ -0,0 +1,113 @@
+package usagesummary
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/theme"
+	"github.com/jquag/ai-mux/transcript"
+)
+
+const (
+	tokensWidth = 12
+	costWidth   = 10
+)
+
+type line struct {
+	name  string
+	usage transcript.Usage
+	err   error
+}
+
+// Model lists the token usage and estimated cost of every open work item with the totals
+type Model struct {
+	lines    []line
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func New(items []*data.WorkItem) *Model {
+	lines := []line{}
+	for _, item := range items {
+		usage, err := transcript.ForItem(item)
+		lines = append(lines, line{name: item.ShortName, usage: usage, err: err})
+	}
+	return &Model{
+		lines:    lines,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	m.viewport.SetContent(m.buildContent())
+	return m.viewport.View()
+}
+
+func (m *Model) buildContent() string {
+	if len(m.lines) == 0 {
+		return lipgloss.NewStyle().Foreground(theme.Colors.Muted).Italic(true).Render("--No work items--")
+	}
+
+	nameWidth := max(10, m.width-tokensWidth-costWidth)
+	nameStyle := lipgloss.NewStyle().Width(nameWidth).MaxWidth(nameWidth).Foreground(theme.Colors.Title)
+	numberStyle := lipgloss.NewStyle().Width(tokensWidth).Align(lipgloss.Right).Foreground(theme.Colors.Text)
+	costStyle := numberStyle.Width(costWidth).Foreground(theme.Colors.Info)
+	headerStyle := lipgloss.NewStyle().Foreground(theme.Colors.Primary).Bold(true)
+	errorStyle := lipgloss.NewStyle().Width(tokensWidth + costWidth).Align(lipgloss.Right).Foreground(theme.Colors.Error)
+
+	rows := []string{lipgloss.JoinHorizontal(lipgloss.Top,
+		headerStyle.Width(nameWidth).Render("Work Item"),
+		headerStyle.Width(tokensWidth).Align(lipgloss.Right).Render("Tokens"),
+		headerStyle.Width(costWidth).Align(lipgloss.Right).Render("Cost"),
+	)}
+
+	total := transcript.Usage{}
+	for _, l := range m.lines {
+		if l.err != nil {
+			rows = append(rows, nameStyle.Render(l.name)+errorStyle.Render("unreadable"))
+			continue
+		}
+		total = total.Add(l.usage)
+		rows = append(rows, nameStyle.Render(l.name)+
+			numberStyle.Render(transcript.FormatTokens(l.usage.Tokens()))+
+			costStyle.Render(transcript.FormatCost(l.usage.Cost)))
+	}
+
+	rows = append(rows,
+		lipgloss.NewStyle().Foreground(theme.Colors.Muted).Render(strings.Repeat("─", nameWidth+tokensWidth+costWidth)),
+		nameStyle.Bold(true).Foreground(theme.Colors.Text).Render("Total")+
+			numberStyle.Bold(true).Render(transcript.FormatTokens(total.Tokens()))+
+			costStyle.Bold(true).Render(transcript.FormatCost(total.Cost)),
+		"",
+		lipgloss.NewStyle().Foreground(theme.Colors.Muted).Width(m.width).Render(
+			"Costs are estimated from list prices and the token counts in the Claude transcripts."),
+	)
+	return strings.Join(rows, "\n")
+}
+
+func (m *Model) ShouldCloseOnEscape() bool {
+	return true
+}
+
+func (m *Model) WithWidth(width int) modal.ModalContent {
+	m.width = min(width, 80)
+	m.viewport.Width = m.width
+	return m
+}
+
+func (m *Model) WithHeight(height int) modal.ModalContent {
+	m.height = height
+	m.viewport.Height = height - 4
+	return m
+}
@@ -0,0 +1,82 @@
+package workform
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	workitem "github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/util"
+)
+
+const (
+	maxShortNameLength   = 50
+	maxDescriptionLength = 20000
+	maxTagLength         = 30
+)
+
+// ValidateShortName returns a huh validator requiring a short name that is unique among the
+// open work items (other than self) and that maps to a valid git branch name
+func ValidateShortName(otherItems []*workitem.WorkItem, self *workitem.WorkItem) func(string) error {
+	return func(s string) error {
+		name := strings.TrimSpace(s)
+		if name == "" {
+			return fmt.Errorf("short name is required")
+		}
+		if len([]rune(name)) > maxShortNameLength {
+			return fmt.Errorf("short name must be at most %d characters", maxShortNameLength)
+		}
+		if !strings.ContainsFunc(name, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			return fmt.Errorf("short name must contain a letter or digit")
+		}
+		if !util.IsValidBranchName(util.ToSafeName(name)) {
+			return fmt.Errorf("%q is not a valid branch name", util.ToSafeName(name))
+		}
+		for _, other := range otherItems {
+			if self != nil && other.Id == self.Id {
+				continue
+			}
+			if strings.EqualFold(strings.TrimSpace(other.ShortName), name) {
+				return fmt.Errorf("another work item is already named %q", other.ShortName)
+			}
+		}
+		return nil
+	}
+}
+
+// ValidateDescription requires a description, since it becomes the prompt the agent starts with
+func ValidateDescription(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return fmt.Errorf("description is required")
+	}
+	if len([]rune(s)) > maxDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", maxDescriptionLength)
+	}
+	return nil
+}
+
+// ValidateTags requires every comma separated tag to be a single short word
+func ValidateTags(s string) error {
+	for _, tag := range ParseTags(s) {
+		if strings.ContainsFunc(tag, unicode.IsSpace) {
+			return fmt.Errorf("tag %q must not contain spaces", tag)
+		}
+		if len([]rune(tag)) > maxTagLength {
+			return fmt.Errorf("tag %q must be at most %d characters", tag, maxTagLength)
+		}
+	}
+	return nil
+}
+
+// ParseTags splits a comma separated list into tags, dropping blanks, a leading # and duplicates
+func ParseTags(s string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -3,25 +3,52 @@ package workform
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/google/uuid"
+	"github.com/jquag/ai-mux/component/alert"
 	"github.com/jquag/ai-mux/component/modal"
 	workitem "github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/keymap"
+	"github.com/jquag/ai-mux/service"
+	"github.com/jquag/ai-mux/theme"
 	"github.com/jquag/ai-mux/util"
 )
 
 type Model struct {
-	form      *huh.Form
-	submitted bool
-	width     int
-	height    int
-	editMode  bool
+	form         *huh.Form
+	submitted    bool
+	width        int
+	height       int
+	editMode     bool
 	existingItem *workitem.WorkItem
+	otherItems   []*workitem.WorkItem
 
-	shortName    string
-	description   string
+	// values are bound to the form fields by pointer so they survive rebuilding the form
+	values *formValues
+}
+
+type formValues struct {
+	shortName          string
+	description        string
+	tags               string
+	startMode          string
+	layout             string
+	autoStart          bool
+	dependsOn          []string
+	baseOnPrerequisite bool
+	confirm            bool
+}
+
+// editorFinishedMsg carries the description back from $EDITOR
+type editorFinishedMsg struct {
+	values  *formValues
+	content string
+	err     error
 }
 
 func (m Model) Init() tea.Cmd {
@@ -33,6 +60,26 @@ func (m Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
 		return m, nil
 	}
 
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, keymap.Keys.Editor) {
+			return m, m.openEditor()
+		}
+	case editorFinishedMsg:
+		if msg.values != m.values {
+			return m, nil
+		}
+		if msg.err != nil {
+			return m, alert.Alert(fmt.Sprintf("Failed to edit description: %v", msg.err), alert.AlertTypeError)
+		}
+		m.values.description = strings.TrimRight(msg.content, "\n")
+
+		// The text area keeps its own copy of the value, so rebuild the form around the
+		// bound values and put the focus back on the description
+		m.form = m.buildForm().WithWidth(m.width)
+		return m, tea.Sequence(m.form.Init(), m.form.NextField())
+	}
+
 	form, cmd := m.form.Update(msg)
 	if f, ok := form.(*huh.Form); ok {
 		m.form = f
@@ -65,63 +112,214 @@ func (m Model) ShouldCloseOnEscape() bool {
 	return true
 }
 
-func New(item *workitem.WorkItem) Model {
+func New(item *workitem.WorkItem, otherItems []*workitem.WorkItem) Model {
 	m := Model{
-		width:  0,
-		height: 0,
-		editMode: item != nil && item.Id != "",
+		width:        0,
+		height:       0,
+		editMode:     item != nil && item.Id != "",
 		existingItem: item,
+		otherItems:   otherItems,
+		values:       &formValues{confirm: true}, // Default to Submit
 	}
-	
+
 	// Set initial values for editing
-	shortNameValue := ""
-	descriptionValue := ""
-	confirmValue := true // Default to Submit
 	if item != nil {
-		shortNameValue = item.ShortName
-		descriptionValue = item.Description
-	}
-
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Key("shortName").
-				Title("Short name").
-				Value(&shortNameValue),
-			huh.NewText().
-				Key("description").
-				Title("Description").
-				Value(&descriptionValue),
-			huh.NewConfirm().
-				Key("done").
-				Value(&confirmValue).
-				Affirmative("Submit (s)").
-				Negative("Cancel (c)"),
-		),
-	).WithWidth(0).WithHeight(0)
-
-	m.form = form
+		m.values.shortName = item.ShortName
+		m.values.description = item.Description
+		m.values.tags = strings.Join(item.Tags, ", ")
+		m.values.startMode = item.StartMode
+		m.values.layout = item.Layout
+		m.values.autoStart = item.AutoStart
+		m.values.dependsOn = item.DependsOn
+		m.values.baseOnPrerequisite = item.BaseOn != ""
+	}
+	if m.values.startMode == "" {
+		m.values.startMode = "default"
+	}
+	if m.values.layout == "" {
+		m.values.layout = workitem.LayoutStacked
+	}
+
+	m.form = m.buildForm()
 	return m
 }
 
+func (m Model) buildForm() *huh.Form {
+	fields := []huh.Field{
+		huh.NewInput().
+			Key("shortName").
+			Title("Short name").
+			Validate(ValidateShortName(m.otherItems, m.existingItem)).
+			Value(&m.values.shortName),
+		huh.NewText().
+			Key("description").
+			Title("Description").
+			Description(keymap.Keys.Editor.Help().Key + " to write it in $EDITOR").
+			ExternalEditor(false).
+			Validate(ValidateDescription).
+			Value(&m.values.description),
+		huh.NewInput().
+			Key("tags").
+			Title("Tags").
+			Description("Comma separated, used to find the item with /").
+			Validate(ValidateTags).
+			Value(&m.values.tags),
+		huh.NewSelect[string]().
+			Key("startMode").
+			Title("Start mode").
+			Options(
+				huh.NewOption("Default (manual accept)", "default"),
+				huh.NewOption("Plan", "plan"),
+				huh.NewOption("Vibe (accept edits)", "acceptEdits"),
+			).
+			Inline(true).
+			Value(&m.values.startMode),
+		huh.NewSelect[string]().
+			Key("layout").
+			Title("Window layout").
+			Options(
+				huh.NewOption("Editor above Claude", workitem.LayoutStacked),
+				huh.NewOption("Side by side", workitem.LayoutSideBySide),
+				huh.NewOption("Claude only", workitem.LayoutClaude),
+			).
+			Inline(true).
+			Value(&m.values.layout),
+		huh.NewConfirm().
+			Key("autoStart").
+			Title("Start automatically").
+			Description("Queue it to start in its start mode when an agent is free").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&m.values.autoStart),
+	}
+
+	// Prerequisites can only be picked before the item is started, its worktree is based on them
+	if candidates := m.prerequisiteOptions(); len(candidates) > 0 && (m.existingItem == nil || !m.existingItem.IsStarted()) {
+		fields = append(fields,
+			huh.NewMultiSelect[string]().
+				Key("dependsOn").
+				Title("Depends on").
+				Description("It won't start until these are done or merged").
+				Options(candidates...).
+				Value(&m.values.dependsOn),
+			huh.NewConfirm().
+				Key("baseOnPrerequisite").
+				Title("Base on prerequisite branch").
+				Description("Create the worktree from the first prerequisite's branch instead of the current one").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.values.baseOnPrerequisite),
+		)
+	}
+
+	fields = append(fields,
+		huh.NewConfirm().
+			Key("done").
+			Value(&m.values.confirm).
+			Affirmative("Submit (s)").
+			Negative("Cancel (c)"),
+	)
+	return huh.NewForm(huh.NewGroup(fields...)).WithTheme(theme.Huh()).WithWidth(0).WithHeight(0)
+}
+
+// prerequisiteOptions lists the items this one may depend on, leaving out itself and the
+// items that already depend on it so no cycle can be made
+func (m Model) prerequisiteOptions() []huh.Option[string] {
+	id := ""
+	if m.existingItem != nil {
+		id = m.existingItem.Id
+	}
+	options := []huh.Option[string]{}
+	for _, other := range m.otherItems {
+		if other.Id == id || (id != "" && m.dependsOn(other, id, map[string]bool{})) {
+			continue
+		}
+		options = append(options, huh.NewOption(other.ShortName, other.Id))
+	}
+	return options
+}
+
+// dependsOn reports whether item depends on the item with id, directly or through others
+func (m Model) dependsOn(item *workitem.WorkItem, id string, seen map[string]bool) bool {
+	if seen[item.Id] {
+		return false
+	}
+	seen[item.Id] = true
+	for _, prerequisite := range item.DependsOn {
+		if prerequisite == id {
+			return true
+		}
+		for _, other := range m.otherItems {
+			if other.Id == prerequisite && m.dependsOn(other, id, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// baseOn is the prerequisite the worktree is created from, the first picked one when asked for.
+// Its branch is looked up at start time since renaming the prerequisite renames its branch.
+func (m Model) baseOn() string {
+	if !m.values.baseOnPrerequisite || len(m.values.dependsOn) == 0 {
+		return ""
+	}
+	return m.values.dependsOn[0]
+}
+
+// openEditor suspends the TUI and opens the description as a markdown file in $EDITOR
+func (m Model) openEditor() tea.Cmd {
+	file, err := os.CreateTemp("", "ai-mux-*.md")
+	if err != nil {
+		return alert.Alert(fmt.Sprintf("Failed to create temp file: %v", err), alert.AlertTypeError)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(m.values.description); err != nil {
+		return alert.Alert(fmt.Sprintf("Failed to write temp file: %v", err), alert.AlertTypeError)
+	}
+
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vim"} // Default fallback
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+
+	values := m.values
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(file.Name())
+		if err != nil {
+			return editorFinishedMsg{values: values, err: err}
+		}
+		content, err := os.ReadFile(file.Name())
+		return editorFinishedMsg{values: values, content: string(content), err: err}
+	})
+}
+
 func (m Model) submitCmd() tea.Cmd {
 	// Check if user clicked Cancel
 	if !m.form.GetBool("done") {
 		// User cancelled, just close the modal
 		return modal.CloseCmd
 	}
-	
+
 	workItem := m.existingItem
-	workItem.ShortName = m.form.GetString("shortName")
+	workItem.ShortName = strings.TrimSpace(m.form.GetString("shortName"))
 	workItem.Description = m.form.GetString("description")
-	
+	workItem.Tags = ParseTags(m.form.GetString("tags"))
+	workItem.StartMode = m.form.GetString("startMode")
+	workItem.Layout = m.form.GetString("layout")
+	workItem.AutoStart = m.form.GetBool("autoStart")
+	if !workItem.IsStarted() {
+		workItem.DependsOn = m.values.dependsOn
+		workItem.BaseOn = m.baseOn()
+	}
+
 	if m.editMode && m.existingItem != nil {
 		// Update the work item file
 		if err := util.UpdateWorkItem(workItem); err != nil {
-			fmt.Fprintf(os.Stderr, "Error updating work item: %v\n", err)
-			os.Exit(1)
+			return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to update work item: %v", err), alert.AlertTypeError))
 		}
-		
+
 		updateWorkItemCmd := func() tea.Msg {
 			return workitem.UpdateWorkItemMsg{
 				WorkItem: workItem,
@@ -130,13 +328,17 @@ func (m Model) submitCmd() tea.Cmd {
 		return tea.Batch(modal.CloseCmd, updateWorkItemCmd)
 	} else {
 		workItem.Id = uuid.New().String()
-		
+
+		// Fix the branch, worktree and window names now so later edits don't move them
+		if err := service.AssignNames(workItem, m.otherItems); err != nil {
+			return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to save work item: %v", err), alert.AlertTypeError))
+		}
+
 		// Save the new work item to file
 		if err := util.SaveWorkItem(workItem); err != nil {
-			fmt.Fprintf(os.Stderr, "Error saving work item: %v\n", err)
-			os.Exit(1)
+			return tea.Sequence(modal.CloseCmd, alert.Alert(fmt.Sprintf("Failed to save work item: %v", err), alert.AlertTypeError))
 		}
-		
+
 		newWorkItemCmd := func() tea.Msg {
 			return workitem.NewWorkItemMsg{
 				WorkItem: workItem,
@@ -145,4 +347,3 @@ func (m Model) submitCmd() tea.Cmd {
 		return tea.Batch(modal.CloseCmd, newWorkItemCmd)
 	}
 }
-
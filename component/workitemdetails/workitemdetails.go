@@ -2,42 +2,124 @@ package workitemdetails
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/component/transcriptview"
 	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/keymap"
 	"github.com/jquag/ai-mux/theme"
+	"github.com/jquag/ai-mux/transcript"
 	"github.com/jquag/ai-mux/util"
 )
 
+// loadedMsg carries what the details read from disk and git for a started item
+type loadedMsg struct {
+	itemId    string
+	timing    data.Timing
+	hasTiming bool
+	usage     transcript.Usage
+	usageErr  error
+	diff      string
+	diffErr   error
+}
+
+// statusUpdatedMsg tells the details of an item to read its session again
+type statusUpdatedMsg struct {
+	itemId string
+}
+
+// rendered is the built content of the view, kept until new data is loaded or the width changes
+type rendered struct {
+	width   int
+	content string
+}
+
 type Model struct {
-	workItem *data.WorkItem
-	viewport viewport.Model
-	width    int
-	height   int
+	workItem   *data.WorkItem
+	otherItems []*data.WorkItem
+	viewport   viewport.Model
+	loaded     *loadedMsg // nil until the first load finishes
+	loading    bool
+	rendered   *rendered // nil when it has to be built again
+	width      int
+	height     int
 }
 
-func New(workItem *data.WorkItem) *Model {
+func New(workItem *data.WorkItem, otherItems []*data.WorkItem) *Model {
 	vp := viewport.New(0, 0)
 	return &Model{
-		workItem: workItem,
-		viewport: vp,
+		workItem:   workItem,
+		otherItems: otherItems,
+		viewport:   vp,
+	}
+}
+
+// Init reads the item's status log, transcript usage and diff in the background
+func (m *Model) Init() tea.Cmd {
+	m.loading = true
+	item := *m.workItem
+	return func() tea.Msg {
+		msg := loadedMsg{itemId: item.Id}
+		if !item.IsStarted() {
+			return msg
+		}
+		if entries, err := util.ReadStatusLog(item.Id); err == nil {
+			msg.timing, msg.hasTiming = data.TimingOf(entries), true
+		}
+		msg.usage, msg.usageErr = transcript.ForItem(&item)
+		if item.WorktreePath != "" {
+			msg.diff, msg.diffErr = util.GetColoredGitDiff(item.WorktreePath)
+		}
+		return msg
+	}
+}
+
+// StatusUpdated has the details of the item, when they are open, read its session again
+func StatusUpdated(itemId string) tea.Cmd {
+	return func() tea.Msg {
+		return statusUpdatedMsg{itemId: itemId}
 	}
 }
 
 func (m *Model) Update(msg tea.Msg) (modal.ModalContent, tea.Cmd) {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		if msg.itemId == m.workItem.Id {
+			m.loading = false
+			m.loaded = &msg
+			m.rendered = nil
+		}
+		return m, nil
+	case statusUpdatedMsg:
+		if msg.itemId == m.workItem.Id && !m.loading {
+			return m, m.Init()
+		}
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, keymap.Keys.Transcript) && m.workItem.IsStarted() {
+		// Shown first so the viewer is on the stack when the transcript is loaded
+		view := transcriptview.New(m.workItem)
+		return m, tea.Sequence(modal.ShowModal(view, "Transcript - "+m.workItem.ShortName), view.Init())
+	}
+
 	var cmd tea.Cmd
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
 }
 
 func (m *Model) View() string {
-	content := m.buildContent()
-	m.viewport.SetContent(content)
+	if m.rendered == nil || m.rendered.width != m.width {
+		m.rendered = &rendered{width: m.width, content: m.buildContent()}
+		m.viewport.SetContent(m.rendered.content)
+	}
 	return m.viewport.View()
 }
 
@@ -47,14 +129,14 @@ func (m *Model) buildContent() string {
 	nameStyle := lipgloss.NewStyle().
 		Foreground(theme.Colors.Primary).
 		Bold(true)
-	
+
 	labelStyle := lipgloss.NewStyle().
 		Foreground(theme.Colors.Text).
 		Bold(true)
-	
+
 	valueStyle := lipgloss.NewStyle().
 		Foreground(theme.Colors.Info)
-	
+
 	descStyle := lipgloss.NewStyle().
 		Foreground(theme.Colors.Text)
 
@@ -66,27 +148,98 @@ func (m *Model) buildContent() string {
 	sections = append(sections, descStyle.Width(m.width).MaxWidth(m.width).Render(m.workItem.Description))
 	sections = append(sections, "")
 
+	if len(m.workItem.Tags) > 0 {
+		sections = append(sections, nameStyle.Render("Tags"))
+		sections = append(sections, valueStyle.Render("#"+strings.Join(m.workItem.Tags, " #")))
+		sections = append(sections, "")
+	}
+
+	if options := m.workItem.Options; options.Model != "" || len(options.AllowedTools) > 0 || len(options.DisallowedTools) > 0 ||
+		options.ExtraArgs != "" || len(options.Env) > 0 {
+		sections = append(sections, nameStyle.Render("Start Options"))
+		if options.Model != "" {
+			sections = append(sections, labelStyle.Render("Model: ")+valueStyle.Render(options.Model))
+		}
+		if len(options.AllowedTools) > 0 {
+			sections = append(sections, labelStyle.Render("Allowed Tools: ")+valueStyle.Render(strings.Join(options.AllowedTools, ", ")))
+		}
+		if len(options.DisallowedTools) > 0 {
+			sections = append(sections, labelStyle.Render("Denied Tools: ")+valueStyle.Render(strings.Join(options.DisallowedTools, ", ")))
+		}
+		if options.ExtraArgs != "" {
+			sections = append(sections, labelStyle.Render("Extra Arguments: ")+valueStyle.Render(options.ExtraArgs))
+		}
+		if len(options.Env) > 0 {
+			names := []string{}
+			for name := range options.Env {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			sections = append(sections, labelStyle.Render("Environment: ")+valueStyle.Render(strings.Join(names, ", ")))
+		}
+		sections = append(sections, "")
+	}
+
+	if len(m.workItem.DependsOn) > 0 {
+		sections = append(sections, nameStyle.Render("Depends On"))
+		for _, id := range m.workItem.DependsOn {
+			for _, other := range m.otherItems {
+				if other.Id == id {
+					sections = append(sections, valueStyle.Render(other.ShortName)+descStyle.Render(" - "+prerequisiteState(other)))
+				}
+				if other.Id == id && id == m.workItem.BaseOn {
+					sections = append(sections, labelStyle.Render("Based On: ")+valueStyle.Render(other.BranchName))
+				}
+			}
+		}
+		sections = append(sections, "")
+	}
+
 	isStarted := m.workItem.Status != "created" && m.workItem.Status != ""
-	
+
 	if isStarted {
 		sections = append(sections, nameStyle.Render("Session Information"))
-		
-		safeName := util.ToSafeName(m.workItem.ShortName)
-		
-		sections = append(sections, labelStyle.Render("Tmux Window: ") + valueStyle.Render(safeName))
-		
-		sections = append(sections, labelStyle.Render("Git Branch: ") + valueStyle.Render(safeName))
-		
-		cwd, err := os.Getwd()
-		var worktreePath string
-		if err == nil {
-			mainFolderName := filepath.Base(cwd)
-			worktreePath = filepath.Join("..", fmt.Sprintf("%s-worktrees", mainFolderName), safeName)
-			sections = append(sections, labelStyle.Render("Worktree Folder: ") + valueStyle.Render(worktreePath))
+
+		sections = append(sections, labelStyle.Render("Tmux Window: ")+valueStyle.Render(m.workItem.WindowName))
+
+		sections = append(sections, labelStyle.Render("Git Branch: ")+valueStyle.Render(m.workItem.BranchName))
+
+		worktreePath := m.workItem.WorktreePath
+		sections = append(sections, labelStyle.Render("Worktree Folder: ")+valueStyle.Render(worktreePath))
+
+		sections = append(sections, labelStyle.Render("Claude Session ID: ")+valueStyle.Render(m.workItem.Id))
+
+		loaded := m.loaded
+		if loaded == nil {
+			sections = append(sections, "", descStyle.Render("Loading..."))
+			return lipgloss.JoinVertical(lipgloss.Left, sections...)
 		}
-		
-		sections = append(sections, labelStyle.Render("Claude Session ID: ") + valueStyle.Render(m.workItem.Id))
-		
+
+		if loaded.hasTiming {
+			timing := loaded.timing
+			now := time.Now()
+			if !timing.StateSince.IsZero() {
+				sections = append(sections, labelStyle.Render("Time in State: ")+valueStyle.Render(util.FormatDuration(timing.InState(now))))
+			}
+			sections = append(sections, labelStyle.Render("Active Time: ")+valueStyle.Render(util.FormatDuration(timing.ActiveAt(now))))
+		}
+
+		sections = append(sections, "", nameStyle.Render("Token Usage"))
+		if loaded.usageErr != nil {
+			sections = append(sections, descStyle.Render(fmt.Sprintf("Error reading transcript: %v", loaded.usageErr)))
+		} else {
+			usage := loaded.usage
+			sections = append(sections,
+				labelStyle.Render("Input: ")+valueStyle.Render(transcript.FormatTokens(usage.InputTokens)),
+				labelStyle.Render("Output: ")+valueStyle.Render(transcript.FormatTokens(usage.OutputTokens)),
+				labelStyle.Render("Cache Write: ")+valueStyle.Render(transcript.FormatTokens(usage.CacheCreationTokens)),
+				labelStyle.Render("Cache Read: ")+valueStyle.Render(transcript.FormatTokens(usage.CacheReadTokens)),
+				labelStyle.Render("Estimated Cost: ")+valueStyle.Render(transcript.FormatCost(usage.Cost)),
+			)
+		}
+		sections = append(sections, lipgloss.NewStyle().Foreground(theme.Colors.Muted).Render(
+			"Press "+keymap.Keys.Transcript.Help().Key+" to read the conversation"))
+
 		// Add git diff section
 		if worktreePath != "" {
 			sections = append(sections, "")
@@ -94,13 +247,12 @@ func (m *Model) buildContent() string {
 				Width(m.width).
 				Border(lipgloss.NormalBorder(), false, false, true, false).BorderForeground(theme.Colors.Muted).
 				Render("Git Diff"))
-			
-			diff, err := util.GetColoredGitDiff(worktreePath)
-			if err != nil {
-				sections = append(sections, descStyle.Render(fmt.Sprintf("Error getting diff: %v", err)))
+
+			if loaded.diffErr != nil {
+				sections = append(sections, descStyle.Render(fmt.Sprintf("Error getting diff: %v", loaded.diffErr)))
 			} else {
 				// The diff already contains ANSI color codes, so we append it directly
-				sections = append(sections, diff)
+				sections = append(sections, loaded.diff)
 			}
 		}
 	}
@@ -123,3 +275,14 @@ func (m *Model) WithHeight(height int) modal.ModalContent {
 	m.viewport.Height = height - 4
 	return m
 }
+
+func prerequisiteState(item *data.WorkItem) string {
+	switch {
+	case item.IsDone():
+		return "done"
+	case item.IsStarted():
+		return "in progress"
+	default:
+		return "not started"
+	}
+}
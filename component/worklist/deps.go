@@ -0,0 +1,79 @@
+package worklist
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/util"
+)
+
+type mergedMsg struct {
+	id     string
+	merged bool
+}
+
+// blockers returns the prerequisites of the item that are not satisfied yet. A prerequisite
+// is satisfied once its agent stops, its branch is merged or it is removed from the list.
+func (m *Model) blockers(item *data.WorkItem) []*data.WorkItem {
+	blockers := []*data.WorkItem{}
+	for _, id := range item.DependsOn {
+		for _, other := range m.workItems {
+			if other.Id == id && !other.IsDone() && !m.merged[id] {
+				blockers = append(blockers, other)
+			}
+		}
+	}
+	return blockers
+}
+
+func blockerNames(blockers []*data.WorkItem) string {
+	names := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		names[i] = blocker.ShortName
+	}
+	return strings.Join(names, ", ")
+}
+
+// isPrerequisite reports whether an item still to be started depends on the item
+func (m *Model) isPrerequisite(item *data.WorkItem) bool {
+	for _, other := range m.workItems {
+		if !other.IsStarted() {
+			for _, id := range other.DependsOn {
+				if id == item.Id {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// baseBranch returns the current branch of the prerequisite the item is based on. It is empty,
+// meaning HEAD, when the prerequisite is merged since its work is in HEAD then.
+func (m *Model) baseBranch(item *data.WorkItem) string {
+	if item.BaseOn == "" || m.merged[item.BaseOn] {
+		return ""
+	}
+	for _, other := range m.workItems {
+		if other.Id == item.BaseOn {
+			return other.BranchName
+		}
+	}
+	return ""
+}
+
+// checkMerged finds out whether the item's branch has been merged into the branch checked out
+// in the main workspace. That branch is read every time since the user may switch it.
+func checkMerged(item *data.WorkItem) tea.Cmd {
+	branch := item.BranchName
+	id := item.Id
+	return func() tea.Msg {
+		mainBranch, err := util.CurrentBranch()
+		if err != nil || branch == "" || mainBranch == "" || !util.BranchExists(branch) {
+			return mergedMsg{id: id}
+		}
+		merged, _ := util.IsBranchMerged(branch, mainBranch)
+		return mergedMsg{id: id, merged: merged}
+	}
+}
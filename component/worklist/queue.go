@@ -0,0 +1,76 @@
+package worklist
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jquag/ai-mux/config"
+	"github.com/jquag/ai-mux/data"
+)
+
+// queued returns the items waiting to be started by the queue, in Order
+func (m *Model) queued() []*data.WorkItem {
+	queued := []*data.WorkItem{}
+	for _, item := range m.workItems {
+		if item.AutoStart && !item.IsStarted() && !item.IsClosing && !m.launched[item.Id] {
+			queued = append(queued, item)
+		}
+	}
+	return queued
+}
+
+// queuePosition returns the item's place in the queue starting at 1, 0 when it isn't queued
+func (m *Model) queuePosition(item *data.WorkItem) int {
+	for i, queued := range m.queued() {
+		if queued.Id == item.Id {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// runQueue starts queued items in their start mode while fewer than the configured
+// number of agents are working
+func (m *Model) runQueue() tea.Cmd {
+	if m.queuePaused {
+		return nil
+	}
+
+	working := 0
+	for _, item := range m.workItems {
+		if item.IsWorking() {
+			working++
+		}
+	}
+
+	cmds := []tea.Cmd{}
+	for _, item := range m.queued() {
+		if working >= config.Values.QueueLimit {
+			break
+		}
+		if len(m.blockers(item)) > 0 {
+			// Blocked items keep their place and later items may start ahead of them
+			continue
+		}
+		mode := item.StartMode
+		if mode == "" {
+			mode = "default"
+		}
+		cmds = append(cmds, m.startItem(item, mode))
+		working++
+	}
+	return tea.Batch(cmds...)
+}
+
+// queueSummary is shown next to the title while items are queued, like " · 3 queued"
+func (m *Model) queueSummary() string {
+	queued := len(m.queued())
+	if queued == 0 {
+		return ""
+	}
+	summary := fmt.Sprintf(" · %d queued, up to %d at once", queued, config.Values.QueueLimit)
+	if m.queuePaused {
+		summary = fmt.Sprintf(" · %d queued, paused", queued)
+	}
+	return summary
+}
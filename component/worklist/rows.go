@@ -0,0 +1,297 @@
+package worklist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/theme"
+	"github.com/jquag/ai-mux/transcript"
+)
+
+// group is a section of the list when items are grouped by status
+type group struct {
+	key   string
+	title string
+}
+
+// groups in the order they are shown, items needing attention first
+var groups = []group{
+	{"waiting", "Waiting for input"},
+	{"working", "Working"},
+	{"done", "Done"},
+	{"notStarted", "Not started"},
+}
+
+func groupOf(item *data.WorkItem) string {
+	switch {
+	case item.IsWaiting():
+		return "waiting"
+	case item.IsWorking():
+		return "working"
+	case !item.IsStarted():
+		return "notStarted"
+	default:
+		return "done"
+	}
+}
+
+// row is one selectable entry of the list, a work item or a group header
+type row struct {
+	item  *data.WorkItem
+	index int    // position of item in workItems
+	group string // group of a header row, item is nil
+	count int    // items in the group of a header row
+}
+
+// rows lists what the list shows, in order. Items keep their manual order within a group
+// and the items of folded groups are left out.
+func (m *Model) rows() []row {
+	if !m.grouped {
+		rows := make([]row, len(m.workItems))
+		for i, item := range m.workItems {
+			rows[i] = row{item: item, index: i}
+		}
+		return rows
+	}
+
+	rows := []row{}
+	for _, g := range groups {
+		members := []row{}
+		for i, item := range m.workItems {
+			if groupOf(item) == g.key {
+				members = append(members, row{item: item, index: i})
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		rows = append(rows, row{group: g.key, count: len(members)})
+		if !m.folded[g.key] {
+			rows = append(rows, members...)
+		}
+	}
+	return rows
+}
+
+// cursor returns the position of the selection in rows. A selected item hidden in a
+// folded group moves the selection to the group's header.
+func (m *Model) cursor(rows []row) int {
+	for i, r := range rows {
+		if m.selectedGroup != "" && r.item == nil && r.group == m.selectedGroup {
+			return i
+		}
+		if m.selectedGroup == "" && r.item != nil && r.index == m.selectedIndex {
+			return i
+		}
+	}
+
+	if item := m.getSelected(); item != nil && m.grouped {
+		m.selectedGroup = groupOf(item)
+		return m.cursor(rows)
+	}
+	if len(rows) > 0 {
+		m.selectRow(rows[0])
+	}
+	return 0
+}
+
+func (m *Model) selectRow(r row) {
+	if r.item == nil {
+		m.selectedGroup = r.group
+		return
+	}
+	m.selectedGroup = ""
+	m.selectedIndex = r.index
+}
+
+// moveCursor moves the selection by delta rows, stopping at either end
+func (m *Model) moveCursor(delta int) {
+	rows := m.rows()
+	if len(rows) == 0 {
+		return
+	}
+	i := max(0, min(len(rows)-1, m.cursor(rows)+delta))
+	m.selectRow(rows[i])
+}
+
+// toggleFold folds or unfolds the group of the selection, leaving its header selected
+func (m *Model) toggleFold() {
+	if !m.grouped {
+		return
+	}
+	key := m.selectedGroup
+	if key == "" {
+		item := m.getSelected()
+		if item == nil {
+			return
+		}
+		key = groupOf(item)
+	}
+	m.folded[key] = !m.folded[key]
+	m.selectedGroup = key
+}
+
+// neighbour returns the index of the item the selected one swaps with when moved by delta,
+// the next item of the same group when grouped
+func (m *Model) neighbour(delta int) (int, bool) {
+	item := m.getSelected()
+	if item == nil {
+		return 0, false
+	}
+	for i := m.selectedIndex + delta; i >= 0 && i < len(m.workItems); i += delta {
+		if !m.grouped || groupOf(m.workItems[i]) == groupOf(item) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (m *Model) rowHeight(r row) int {
+	switch {
+	case r.item == nil:
+		return groupHeight
+	case m.compact:
+		return 1
+	default:
+		return itemHeight
+	}
+}
+
+func (m *Model) listHeight() int {
+	return max(1, m.height-headerHeight)
+}
+
+// listBody renders only the rows in the visible window, scrolling it first so the
+// selected row is fully shown
+func (m *Model) listBody() string {
+	rows := m.rows()
+	cursor := m.cursor(rows)
+	height := m.listHeight()
+
+	tops := make([]int, len(rows))
+	total := 0
+	for i, r := range rows {
+		tops[i] = total
+		total += m.rowHeight(r)
+	}
+
+	selectedTop := tops[cursor]
+	selectedBottom := selectedTop + m.rowHeight(rows[cursor])
+	if selectedTop < m.offset {
+		m.offset = selectedTop
+	}
+	if selectedBottom > m.offset+height {
+		m.offset = selectedBottom - height
+	}
+	// Don't leave blank space below the last row after items are removed
+	m.offset = max(0, min(m.offset, total-height))
+
+	lines := []string{}
+	for i, r := range rows {
+		if tops[i]+m.rowHeight(r) <= m.offset {
+			continue
+		}
+		if tops[i] >= m.offset+height {
+			break
+		}
+
+		rowLines := strings.Split(m.rowView(r, i == cursor), "\n")
+		start := max(0, m.offset-tops[i])
+		end := min(len(rowLines), m.offset+height-tops[i])
+		lines = append(lines, rowLines[start:end]...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rowAt returns the row rendered at line y of the worklist
+func (m *Model) rowAt(y int) (row, bool) {
+	line := y - headerHeight + m.offset
+	if y < headerHeight {
+		return row{}, false
+	}
+	top := 0
+	for _, r := range m.rows() {
+		bottom := top + m.rowHeight(r)
+		if line >= top && line < bottom {
+			return r, true
+		}
+		top = bottom
+	}
+	return row{}, false
+}
+
+func (m *Model) rowView(r row, selected bool) string {
+	switch {
+	case r.item == nil:
+		return m.groupView(r, selected)
+	case m.compact:
+		return m.compactItemView(r.item, selected)
+	default:
+		return m.itemView(r.item, selected)
+	}
+}
+
+func (m *Model) groupView(r row, selected bool) string {
+	bg := lipgloss.NewStyle()
+	if selected {
+		bg = theme.SelectedStyle()
+	}
+	color := theme.Colors.Primary
+	if m.Overlayed {
+		color = theme.Colors.Muted
+	}
+
+	title := r.group
+	for _, g := range groups {
+		if g.key == r.group {
+			title = g.title
+		}
+	}
+	arrow := "▾"
+	if m.folded[r.group] {
+		arrow = "▸"
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).MaxWidth(m.width).
+		Foreground(color).Bold(true).
+		Inherit(bg).
+		Render(fmt.Sprintf("%s %s (%d)", arrow, title, r.count))
+}
+
+// compactItemView renders an item on one line: name, tags and the start of the description
+// with the status on the right
+func (m *Model) compactItemView(item *data.WorkItem, selected bool) string {
+	bg := lipgloss.NewStyle()
+	if selected {
+		bg = theme.SelectedStyle()
+	}
+	nameColor := theme.Colors.Title
+	if m.Overlayed {
+		nameColor = theme.Colors.Muted
+	}
+	statusStyle := lipgloss.NewStyle().Foreground(m.colorForStatus(item)).Bold(m.isOverdue(item)).Inherit(bg)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Colors.Muted).Inherit(bg)
+
+	bullet := statusStyle.Render("● ")
+	status := statusStyle.Render(" " + m.stateText(item))
+	if usage := m.usage[item.Id]; usage.Tokens() > 0 {
+		status = mutedStyle.Render(" "+transcript.FormatCost(usage.Cost)) + status
+	}
+
+	left := lipgloss.NewStyle().Foreground(nameColor).Inherit(bg).Render(item.ShortName)
+	if len(item.Tags) > 0 {
+		left += mutedStyle.Render("  #" + strings.Join(item.Tags, " #"))
+	}
+	if description, _, _ := strings.Cut(strings.TrimSpace(item.Description), "\n"); description != "" {
+		left += mutedStyle.Render("  " + description)
+	}
+
+	available := max(0, m.width-lipgloss.Width(bullet)-lipgloss.Width(status))
+	left = ansi.Truncate(left, available, "…")
+	gap := bg.Render(strings.Repeat(" ", available-lipgloss.Width(left)))
+	return bullet + left + gap + status
+}
@@ -0,0 +1,215 @@
+package worklist
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jquag/ai-mux/data"
+)
+
+// testItems are work items named item-a, item-b… with the given statuses
+func testItems(statuses ...string) []*data.WorkItem {
+	items := make([]*data.WorkItem, len(statuses))
+	for i, status := range statuses {
+		name := fmt.Sprintf("item-%c", 'a'+i)
+		items[i] = &data.WorkItem{Id: name, ShortName: name, Status: status, Order: i}
+	}
+	return items
+}
+
+// rowNames describes rows as item names and "#group (count)" for headers
+func rowNames(rows []row) []string {
+	names := make([]string, len(rows))
+	for i, r := range rows {
+		if r.item == nil {
+			names[i] = fmt.Sprintf("#%s (%d)", r.group, r.count)
+		} else {
+			names[i] = r.item.ShortName
+		}
+	}
+	return names
+}
+
+func TestRows(t *testing.T) {
+	statuses := []string{"created", "Notification", "Stop", "PreToolUse", "created"}
+
+	tests := []struct {
+		name    string
+		grouped bool
+		folded  []string
+		want    []string
+	}{
+		{
+			name: "list order",
+			want: []string{"item-a", "item-b", "item-c", "item-d", "item-e"},
+		},
+		{
+			name:    "grouped by status, waiting first",
+			grouped: true,
+			want: []string{
+				"#waiting (1)", "item-b",
+				"#working (1)", "item-d",
+				"#done (1)", "item-c",
+				"#notStarted (2)", "item-a", "item-e",
+			},
+		},
+		{
+			name:    "folded groups keep their header",
+			grouped: true,
+			folded:  []string{"notStarted", "working"},
+			want: []string{
+				"#waiting (1)", "item-b",
+				"#working (1)",
+				"#done (1)", "item-c",
+				"#notStarted (2)",
+			},
+		},
+		{
+			name:   "folding does nothing when not grouped",
+			folded: []string{"notStarted"},
+			want:   []string{"item-a", "item-b", "item-c", "item-d", "item-e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(40, 20)
+			m.workItems = testItems(statuses...)
+			m.grouped = tt.grouped
+			for _, g := range tt.folded {
+				m.folded[g] = true
+			}
+
+			if got := rowNames(m.rows()); !slices.Equal(got, tt.want) {
+				t.Errorf("rows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []string
+		compact       bool
+		grouped       bool
+		listHeight    int
+		offset        int // scroll position before rendering
+		selected      int
+		selectedGroup string
+		wantOffset    int
+		wantLines     []string // start of each line shown
+	}{
+		{
+			name:       "top of the list",
+			statuses:   []string{"", "", "", "", "", ""},
+			compact:    true,
+			listHeight: 3,
+			selected:   1,
+			wantLines:  []string{"● item-a", "● item-b", "● item-c"},
+		},
+		{
+			name:       "scrolls down to the selection",
+			statuses:   []string{"", "", "", "", "", ""},
+			compact:    true,
+			listHeight: 3,
+			selected:   4,
+			wantOffset: 2,
+			wantLines:  []string{"● item-c", "● item-d", "● item-e"},
+		},
+		{
+			name:       "scrolls up to the selection",
+			statuses:   []string{"", "", "", "", "", ""},
+			compact:    true,
+			listHeight: 3,
+			offset:     3,
+			selected:   1,
+			wantOffset: 1,
+			wantLines:  []string{"● item-b", "● item-c", "● item-d"},
+		},
+		{
+			name:       "selection already shown keeps the scroll",
+			statuses:   []string{"", "", "", "", "", ""},
+			compact:    true,
+			listHeight: 3,
+			offset:     2,
+			selected:   3,
+			wantOffset: 2,
+			wantLines:  []string{"● item-c", "● item-d", "● item-e"},
+		},
+		{
+			name:       "no blank space below the last row",
+			statuses:   []string{"", "", "", "", "", ""},
+			compact:    true,
+			listHeight: 3,
+			offset:     5,
+			selected:   5,
+			wantOffset: 3,
+			wantLines:  []string{"● item-d", "● item-e", "● item-f"},
+		},
+		{
+			name:       "list shorter than the window",
+			statuses:   []string{"", ""},
+			compact:    true,
+			listHeight: 5,
+			offset:     2,
+			selected:   0,
+			wantLines:  []string{"● item-a", "● item-b"},
+		},
+		{
+			name:       "tall rows are cut at the window edge",
+			statuses:   []string{"", "", ""},
+			listHeight: 6,
+			selected:   1,
+			wantOffset: 2,
+			wantLines:  []string{"│", "╰─", "● item-b", "│", "│", "╰─"},
+		},
+		{
+			name:       "scrolls up to show all of a tall row",
+			statuses:   []string{"", "", ""},
+			listHeight: 5,
+			offset:     1,
+			selected:   0,
+			wantOffset: 0,
+			wantLines:  []string{"● item-a", "│", "│", "╰─", "● item-b"},
+		},
+		{
+			name:          "group header selected",
+			statuses:      []string{"Stop", "", "Notification", ""},
+			compact:       true,
+			grouped:       true,
+			listHeight:    3,
+			selectedGroup: "notStarted",
+			wantOffset:    2,
+			wantLines:     []string{"▾ Done (1)", "● item-a", "▾ Not started (2)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(40, tt.listHeight+headerHeight)
+			m.workItems = testItems(tt.statuses...)
+			m.compact = tt.compact
+			m.grouped = tt.grouped
+			m.offset = tt.offset
+			m.selectedIndex = tt.selected
+			m.selectedGroup = tt.selectedGroup
+
+			lines := strings.Split(ansi.Strip(m.listBody()), "\n")
+			if m.offset != tt.wantOffset {
+				t.Errorf("listBody() scrolled to %d, want %d", m.offset, tt.wantOffset)
+			}
+			if len(lines) != len(tt.wantLines) {
+				t.Fatalf("listBody() shows %d lines, want %d:\n%s", len(lines), len(tt.wantLines), strings.Join(lines, "\n"))
+			}
+			for i, want := range tt.wantLines {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("line %d = %q, want it to start with %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
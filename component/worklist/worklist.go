@@ -9,17 +9,30 @@ import (
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jquag/ai-mux/component/alert"
+	"github.com/jquag/ai-mux/component/confirm"
+	"github.com/jquag/ai-mux/component/finder"
 	"github.com/jquag/ai-mux/component/help"
+	"github.com/jquag/ai-mux/component/importform"
 	"github.com/jquag/ai-mux/component/modal"
+	"github.com/jquag/ai-mux/component/renameform"
+	"github.com/jquag/ai-mux/component/startform"
+	"github.com/jquag/ai-mux/component/templateform"
+	"github.com/jquag/ai-mux/component/toast"
+	"github.com/jquag/ai-mux/component/usagesummary"
 	"github.com/jquag/ai-mux/component/workform"
 	"github.com/jquag/ai-mux/component/workitemdetails"
+	"github.com/jquag/ai-mux/config"
 	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/keymap"
 	"github.com/jquag/ai-mux/service"
+	"github.com/jquag/ai-mux/settings"
+	"github.com/jquag/ai-mux/templates"
 	"github.com/jquag/ai-mux/theme"
+	"github.com/jquag/ai-mux/transcript"
 	"github.com/jquag/ai-mux/util"
 	"slices"
 )
@@ -27,13 +40,40 @@ import (
 type Model struct {
 	width         int
 	height        int
-	viewport      viewport.Model
 	workItems     []*data.WorkItem
 	Overlayed     bool
 	loading       bool
 	selectedIndex int
+	selectedGroup string // set when a group header is selected instead of an item
+	grouped       bool
+	compact       bool
+	folded        map[string]bool
+	usage         map[string]transcript.Usage // by item id, refreshed with the status
+	timing        map[string]data.Timing      // by item id, refreshed with the status
+	queuePaused   bool
+	merged        map[string]bool      // prerequisites by id whose branch is merged
+	mergeChecked  map[string]time.Time // when the merge of each prerequisite was last checked
+	hookWarned    map[string]bool      // items warned about for not reporting hook events
+	launched      map[string]bool      // items started this run, a poll read before the start can't requeue them
+	offset        int                  // first line of the list shown, scrolled to keep the selection visible
+	lastClick     time.Time
+	lastClickItem int
 }
 
+const (
+	// headerHeight is the title, its underline and a blank line above the items
+	headerHeight = 3
+	itemHeight   = 4
+	// groupHeight is a group header row
+	groupHeight = 1
+
+	doubleClickInterval = 400 * time.Millisecond
+	// hookTimeout is how long a session may stay Starting before its hooks are suspected
+	hookTimeout = 90 * time.Second
+	// mergeCheckInterval is how often a prerequisite's branch is checked for being merged
+	mergeCheckInterval = 30 * time.Second
+)
+
 func (m *Model) Init() tea.Cmd {
 	m.loading = true
 	return loadWorkItems
@@ -42,56 +82,115 @@ func (m *Model) Init() tea.Cmd {
 func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "a":
-			form := workform.New(&data.WorkItem{Order: m.nextWorkItemOrder()})
-			initCmd := form.Init()
-			return m, tea.Batch(initCmd, modal.ShowModal(form, "Add Work Item"))
-		case "j", "down":
-			if len(m.workItems) > m.selectedIndex+1 {
-				m.selectedIndex++
+		switch {
+		case key.Matches(msg, keymap.Keys.Add):
+			list, err := templates.List()
+			if err != nil {
+				return m, alert.Alert(fmt.Sprintf("Failed to load templates: %v", err), alert.AlertTypeError)
+			}
+			if len(list) == 0 {
+				return m, m.showAddForm(&data.WorkItem{Order: m.nextWorkItemOrder()})
 			}
-		case "k", "up":
-			if m.selectedIndex > 0 {
-				m.selectedIndex--
+			picker := templateform.New(list, m.nextWorkItemOrder())
+			return m, tea.Batch(picker.Init(), modal.ShowModal(picker, "Add Work Item"))
+		case key.Matches(msg, keymap.Keys.Template):
+			selected := m.getSelected()
+			if selected != nil {
+				form := templateform.NewSave(selected)
+				return m, tea.Batch(form.Init(), modal.ShowModal(form, "Save as Template"))
 			}
-		case "ctrl+j":
-			if m.selectedIndex < len(m.workItems)-1 {
-				return m, m.moveItemDown(m.selectedIndex)
+		case key.Matches(msg, keymap.Keys.Import):
+			form, err := importform.New(m.nextWorkItemOrder(), m.workItems)
+			if err != nil {
+				return m, alert.Alert(fmt.Sprintf("Failed to list branches: %v", err), alert.AlertTypeError)
 			}
-		case "ctrl+k":
-			if m.selectedIndex > 0 {
-				return m, m.moveItemUp(m.selectedIndex)
+			if !form.HasSources() {
+				return m, toast.Toast("There are no branches or worktrees to import.", alert.AlertTypeInfo)
+			}
+			return m, tea.Batch(form.Init(), modal.ShowModal(form, "Import Branch or Worktree"))
+		case key.Matches(msg, keymap.Keys.Down):
+			m.moveCursor(1)
+		case key.Matches(msg, keymap.Keys.Up):
+			m.moveCursor(-1)
+		case key.Matches(msg, keymap.Keys.MoveDown):
+			if other, ok := m.neighbour(1); ok {
+				return m, m.moveItem(m.selectedIndex, other)
+			}
+		case key.Matches(msg, keymap.Keys.MoveUp):
+			if other, ok := m.neighbour(-1); ok {
+				return m, m.moveItem(m.selectedIndex, other)
+			}
+		case key.Matches(msg, keymap.Keys.Group):
+			m.grouped = !m.grouped
+			m.selectedGroup = ""
+		case key.Matches(msg, keymap.Keys.Fold):
+			m.toggleFold()
+		case key.Matches(msg, keymap.Keys.Density):
+			m.compact = !m.compact
+		case key.Matches(msg, keymap.Keys.Details):
+			if m.selectedGroup != "" {
+				m.toggleFold()
+				return m, nil
 			}
-		case "enter":
 			selected := m.getSelected()
 			if selected != nil {
-				details := workitemdetails.New(selected)
-				return m, modal.ShowModal(details, "Work Item Details")
+				details := workitemdetails.New(selected, m.workItems)
+				return m, tea.Sequence(modal.ShowModal(details, "Work Item Details"), details.Init())
 			}
-		case "s":
+		case key.Matches(msg, keymap.Keys.Start):
 			return m, m.startSelected("default")
-		case "p":
+		case key.Matches(msg, keymap.Keys.StartWith):
+			if cmd := m.checkStartable(m.getSelected()); cmd != nil {
+				return m, cmd
+			}
+			form := startform.New(m.getSelected())
+			return m, tea.Batch(form.Init(), modal.ShowModal(form, "Start "+m.getSelected().ShortName))
+		case key.Matches(msg, keymap.Keys.Plan):
 			return m, m.startSelected("plan")
-		case "v":
+		case key.Matches(msg, keymap.Keys.Vibe):
 			return m, m.startSelected("acceptEdits")
-		case "r":
+		case key.Matches(msg, keymap.Keys.Resume):
 			return m, m.resumeSelected()
-		case "c":
+		case key.Matches(msg, keymap.Keys.Close):
 			return m, m.closeSelected()
-		case "o":
+		case key.Matches(msg, keymap.Keys.Open):
 			return m, m.openSelected()
-		case "e":
+		case key.Matches(msg, keymap.Keys.Approve):
+			return m, m.approveSelected()
+		case key.Matches(msg, keymap.Keys.Edit):
 			selected := m.getSelected()
 			if selected != nil {
-				form := workform.New(selected)
+				form := workform.New(selected, m.workItems)
 				initCmd := form.Init()
 				return m, tea.Batch(initCmd, modal.ShowModal(form, "Edit Work Item"))
 			}
-		case "?":
+		case key.Matches(msg, keymap.Keys.Rename):
+			selected := m.getSelected()
+			if selected != nil {
+				form := renameform.New(selected, m.workItems)
+				initCmd := form.Init()
+				return m, tea.Batch(initCmd, modal.ShowModal(form, "Rename Branch and Window"))
+			}
+		case key.Matches(msg, keymap.Keys.Find):
+			finder := finder.New(m.workItems, m.getSelected())
+			return m, tea.Batch(finder.Init(), modal.ShowModal(finder, "Find Work Item"))
+		case key.Matches(msg, keymap.Keys.Commands):
+			finder := finder.NewCommands(m.workItems, m.getSelected())
+			return m, tea.Batch(finder.Init(), modal.ShowModal(finder, "Commands"))
+		case key.Matches(msg, keymap.Keys.Queue):
+			m.queuePaused = !m.queuePaused
+			if m.queuePaused {
+				return m, toast.Toast("Auto start queue paused.", alert.AlertTypeInfo)
+			}
+			return m, tea.Batch(toast.Toast("Auto start queue resumed.", alert.AlertTypeInfo), m.runQueue())
+		case key.Matches(msg, keymap.Keys.Usage):
+			return m, modal.ShowModal(usagesummary.New(m.workItems), "Token Usage")
+		case key.Matches(msg, keymap.Keys.Help):
 			help := help.New()
 			return m, modal.ShowModal(help, "Help - Key Bindings")
 		}
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
 	case data.NewWorkItemMsg:
 		m.workItems = append(m.workItems, msg.WorkItem)
 		return m, m.startStatusPoller(msg.WorkItem)
@@ -107,6 +206,24 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 			}
 		}
 		return m, nil
+	case startform.StartMsg:
+		if cmd := m.checkStartable(msg.Item); cmd != nil {
+			return m, cmd
+		}
+		return m, m.startItem(msg.Item, msg.Mode)
+	case templateform.ChosenMsg:
+		return m, m.showAddForm(msg.Item)
+	case finder.SelectMsg:
+		for i, item := range m.workItems {
+			if item.Id == msg.Id {
+				m.selectedIndex = i
+				m.selectedGroup = ""
+				delete(m.folded, groupOf(item))
+			}
+		}
+		return m, nil
+	case closeConfirmedMsg:
+		return m, m.closeItem(msg.item)
 	case data.WorkItemRemovedMsg:
 		m.removeWorkItem(msg.WorkItem.Id)
 		return m, nil
@@ -116,12 +233,44 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 		//TODO: handle error
 		return m, m.startStatusPollers()
 	case statusUpdateMsg:
-		m.updateStatus(msg.item, msg.status)
-		if msg.item.IsClosing && msg.status == "Stop" {
+		// Act on the item in the list, edits and renames replace the copy the poller started with
+		item := m.workItem(msg.item.Id)
+		if item == nil {
+			// Removed, its poller stops here
+			return m, nil
+		}
+		status := msg.status
+		if m.launched[item.Id] && (status == "created" || status == "") {
+			// Read before the start was logged, it would make the item look unstarted again
+			status = "Starting"
+		}
+		item.Status = status
+		m.usage[item.Id] = msg.usage
+		m.timing[item.Id] = msg.timing
+		// One time reads refresh the status right away, the item's poller keeps going on its own
+		poll := calcStatus(item, 3, false)
+		if msg.oneTime {
+			poll = nil
+		}
+		if item.IsClosing && status == "Stop" {
 			//finished preping for close
-			return m, tea.Batch(m.closeSelected(), calcStatus(msg.item, 3, false))
+			return m, tea.Batch(m.closeItem(item), poll, m.runQueue())
+		}
+		cmds := []tea.Cmd{poll, m.runQueue(), workitemdetails.StatusUpdated(item.Id)}
+		if status == "Starting" && msg.timing.InState(time.Now()) > hookTimeout && !m.hookWarned[item.Id] {
+			m.hookWarned[item.Id] = true
+			cmds = append(cmds, alert.Alert(fmt.Sprintf(
+				"No events from the Claude session of %s after %s. Check that Claude started in its window and that the hooks in %s run ai-mux.",
+				item.ShortName, util.FormatDuration(hookTimeout), filepath.Join(util.AiMuxDir, item.Id, settings.FileName)), alert.AlertTypeWarning))
 		}
-		return m, calcStatus(msg.item, 3, false)
+		if m.isPrerequisite(item) && !item.IsDone() && time.Since(m.mergeChecked[item.Id]) > mergeCheckInterval {
+			m.mergeChecked[item.Id] = time.Now()
+			cmds = append(cmds, checkMerged(item))
+		}
+		return m, tea.Batch(cmds...)
+	case mergedMsg:
+		m.merged[msg.id] = msg.merged
+		return m, m.runQueue()
 	}
 
 	return m, nil
@@ -140,7 +289,7 @@ func (m *Model) View() string {
 		Foreground(titleColor).
 		Border(lipgloss.NormalBorder(), false, false, true).BorderForeground(borderColor).
 		Width(m.width).
-		Render("Work Items")
+		Render("Work Items" + lipgloss.NewStyle().Foreground(theme.Colors.Muted).Render(m.queueSummary()))
 	body := ""
 
 	if m.loading {
@@ -151,13 +300,11 @@ func (m *Model) View() string {
 		body = m.listBody()
 	}
 
-	m.viewport.SetContent(fmt.Sprintf("%s\n\n%s", title, body))
-
 	var style = lipgloss.NewStyle().
 		Width(m.width).
-		Height(m.height)
+		Height(m.height).MaxHeight(m.height)
 
-	return style.Render(m.viewport.View())
+	return style.Render(fmt.Sprintf("%s\n\n%s", title, body))
 }
 
 func (m *Model) emptyBody() string {
@@ -167,25 +314,17 @@ func (m *Model) emptyBody() string {
 
 	if !m.Overlayed {
 		body += "\n\n[Press " +
-			lipgloss.NewStyle().Foreground(theme.Colors.Primary).Render("a") +
+			lipgloss.NewStyle().Foreground(theme.Colors.Primary).Render(keymap.Keys.Add.Help().Key) +
 			" to add a work item.]"
 	}
 
 	return body
 }
 
-func (m *Model) listBody() string {
-	items := make([]string, len(m.workItems))
-	for i, item := range m.workItems {
-		items[i] = m.itemView(item, i == m.selectedIndex)
-	}
-	return lipgloss.JoinVertical(lipgloss.Left, items...)
-}
-
 func (m *Model) itemView(item *data.WorkItem, selected bool) string {
 	bg := lipgloss.NewStyle()
 	if selected {
-		bg = bg.Background(theme.Colors.BgDark)
+		bg = theme.SelectedStyle()
 	}
 	lineStyle := lipgloss.NewStyle().Foreground(m.colorForStatus(item)).Inherit(bg)
 	left := lipgloss.JoinVertical(lipgloss.Left,
@@ -204,11 +343,15 @@ func (m *Model) itemView(item *data.WorkItem, selected bool) string {
 	}
 
 	centerWidth := m.width - lipgloss.Width(left) - 1
+	tags := ""
+	if len(item.Tags) > 0 {
+		tags = "  #" + strings.Join(item.Tags, " #")
+	}
 	name := lipgloss.NewStyle().
 		Width(centerWidth).MaxWidth(centerWidth).MaxHeight(1).
-		Foreground(nameColor).
 		Inherit(bg).
-		Render(item.ShortName)
+		Render(lipgloss.NewStyle().Foreground(nameColor).Inherit(bg).Render(item.ShortName) +
+			lipgloss.NewStyle().Foreground(theme.Colors.Muted).Inherit(bg).Render(tags))
 	descr := lipgloss.NewStyle().
 		Height(2).MaxHeight(2).Width(centerWidth).
 		Foreground(descriptionColor).
@@ -218,7 +361,7 @@ func (m *Model) itemView(item *data.WorkItem, selected bool) string {
 
 	right := ""
 	// Check if name was truncated
-	if lipgloss.Width(item.ShortName) > centerWidth {
+	if lipgloss.Width(item.ShortName+tags) > centerWidth {
 		right = lipgloss.NewStyle().Foreground(theme.Colors.Muted).Inherit(bg).Render("…")
 	} else {
 		right = lipgloss.NewStyle().Foreground(theme.Colors.Muted).Inherit(bg).Render(" ")
@@ -240,8 +383,61 @@ func (m *Model) itemView(item *data.WorkItem, selected bool) string {
 func (m *Model) statusView(item *data.WorkItem, selected bool) string {
 	bg := lipgloss.NewStyle()
 	if selected {
-		bg = bg.Background(theme.Colors.BgDark)
+		bg = theme.SelectedStyle()
 	}
+	statusStyle := lipgloss.NewStyle().Foreground(m.colorForStatus(item)).Bold(m.isOverdue(item)).Inherit(bg)
+	status := statusStyle.Render(fmt.Sprintf("[%s]", m.stateText(item)))
+	if details := m.detailsText(item); details != "" {
+		status += lipgloss.NewStyle().Foreground(theme.Colors.Muted).Inherit(bg).Render("  " + details)
+	}
+	return lipgloss.NewStyle().Width(m.width - 3).MaxWidth(m.width - 3).Inherit(bg).Render(status)
+}
+
+// stateText is the status with how long the item has been in it, like "Waiting for input · 12m"
+func (m *Model) stateText(item *data.WorkItem) string {
+	status := statusText(item)
+	position := m.queuePosition(item)
+	if position > 0 {
+		status = fmt.Sprintf("Queued #%d", position)
+		if m.queuePaused {
+			status += " (paused)"
+		}
+	}
+	if blockers := m.blockers(item); !item.IsStarted() && len(blockers) > 0 {
+		if position > 0 {
+			status += ", blocked by " + blockerNames(blockers)
+		} else {
+			status = "Blocked by " + blockerNames(blockers)
+		}
+	}
+	timing := m.timing[item.Id]
+	if !item.IsStarted() || timing.StateSince.IsZero() {
+		return status
+	}
+	return status + " · " + util.FormatDuration(timing.InState(time.Now()))
+}
+
+// detailsText summarizes the active time, tokens and cost of the item's session, empty
+// before it has any
+func (m *Model) detailsText(item *data.WorkItem) string {
+	parts := []string{}
+	if active := m.timing[item.Id].ActiveAt(time.Now()); active > 0 {
+		parts = append(parts, "active "+util.FormatDuration(active))
+	}
+	if usage := m.usage[item.Id]; usage.Tokens() > 0 {
+		parts = append(parts, transcript.FormatTokens(usage.Tokens())+" tokens", transcript.FormatCost(usage.Cost))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// isOverdue reports whether the item has been waiting for input longer than the configured limit
+func (m *Model) isOverdue(item *data.WorkItem) bool {
+	limit := time.Duration(config.Values.WaitingAlertMinutes) * time.Minute
+	timing := m.timing[item.Id]
+	return item.IsWaiting() && limit > 0 && !timing.StateSince.IsZero() && timing.InState(time.Now()) >= limit
+}
+
+func statusText(item *data.WorkItem) string {
 	status := ""
 
 	switch item.Status {
@@ -257,6 +453,8 @@ func (m *Model) statusView(item *data.WorkItem, selected bool) string {
 		status = "Not Started"
 	case "PrepForClosing":
 		status = "Closing..."
+	case "Imported":
+		status = "Imported (resume with " + keymap.Keys.Resume.Help().Key + ")"
 	default:
 		status = "Unknown"
 	}
@@ -264,9 +462,7 @@ func (m *Model) statusView(item *data.WorkItem, selected bool) string {
 	if item.Status != "Notification" && item.IsClosing {
 		status = "Closing..."
 	}
-
-	statusStyle := lipgloss.NewStyle().Foreground(m.colorForStatus(item)).Width(m.width - 3).Inherit(bg)
-	return statusStyle.Render(fmt.Sprintf("[%s]", status))
+	return status
 }
 
 func (m *Model) colorForStatus(item *data.WorkItem) lipgloss.TerminalColor {
@@ -278,12 +474,24 @@ func (m *Model) colorForStatus(item *data.WorkItem) lipgloss.TerminalColor {
 		return theme.Colors.Error
 	}
 
+	if m.isOverdue(item) {
+		return theme.Colors.Error
+	}
+
+	if !item.IsStarted() && len(m.blockers(item)) > 0 {
+		return theme.Colors.Muted
+	}
+
+	if m.queuePosition(item) > 0 {
+		return theme.Colors.Info
+	}
+
 	switch item.Status {
 	case "PreToolUse", "PostToolUse", "UserPromptSubmit", "Starting":
 		return theme.Colors.Success
 	case "Notification":
 		return theme.Colors.Primary
-	case "Stop":
+	case "Stop", "Imported":
 		return theme.Colors.Info
 	case "", "created":
 		return theme.Colors.Muted
@@ -295,12 +503,10 @@ func (m *Model) colorForStatus(item *data.WorkItem) lipgloss.TerminalColor {
 }
 
 func (m *Model) SetWidth(width int) {
-	m.viewport.Width = width
 	m.width = width
 }
 
 func (m *Model) SetHeight(height int) {
-	m.viewport.Height = height
 	m.height = height
 }
 
@@ -317,41 +523,50 @@ func (m *Model) startStatusPoller(item *data.WorkItem) tea.Cmd {
 	return calcStatus(item, 0, false)
 }
 
-func (m *Model) updateStatus(item *data.WorkItem, status string) {
-	for _, existingItem := range m.workItems {
-		if existingItem == item {
-			existingItem.Status = status
-			break
-		}
+func (m *Model) startSelected(mode string) tea.Cmd {
+	selected := m.getSelected()
+	if cmd := m.checkStartable(selected); cmd != nil {
+		return cmd
 	}
+	return m.startItem(selected, mode)
 }
 
-func (m *Model) startSelected(mode string) tea.Cmd {
-	selected := m.getSelected()
-	if selected == nil || (selected.Status != "created" && selected.Status != "") {
-		return alert.Alert("This work item has alredy been started.", alert.AlertTypeWarning)
+// checkStartable returns a toast explaining why the item can't be started, nil when it can
+func (m *Model) checkStartable(item *data.WorkItem) tea.Cmd {
+	if item == nil || (item.Status != "created" && item.Status != "") {
+		return toast.Toast("This work item has alredy been started.", alert.AlertTypeWarning)
+	}
+	if blockers := m.blockers(item); len(blockers) > 0 {
+		return toast.Toast("Waiting on "+blockerNames(blockers)+" to finish or be merged first.", alert.AlertTypeWarning)
 	}
+	return nil
+}
 
+// startItem starts the item's session. The status is set right away, not only in the log,
+// so the queue counts it as working before the next poll.
+func (m *Model) startItem(item *data.WorkItem, mode string) tea.Cmd {
 	// Write PrepStarting status
-	util.WriteStatusLog(selected.Id, "Starting", util.AiMuxDir)
+	util.WriteStatusLog(item.Id, "Starting", util.AiMuxDir)
+	item.Status = "Starting"
+	m.launched[item.Id] = true
 
-	return tea.Batch(calcStatus(selected, 0, true), service.StartSession(selected, mode))
+	return tea.Batch(calcStatus(item, 0, true), service.StartSession(item, mode, m.baseBranch(item)))
 }
 
 func (m *Model) resumeSelected() tea.Cmd {
 	selected := m.getSelected()
 	if selected == nil {
-		return alert.Alert("No work item selected.", alert.AlertTypeWarning)
+		return toast.Toast("No work item selected.", alert.AlertTypeWarning)
 	}
-	
+
 	// Check if item has been started (has a session to resume)
 	if selected.Status == "created" || selected.Status == "" {
-		return alert.Alert("This work item has not been started yet.", alert.AlertTypeWarning)
+		return toast.Toast("This work item has not been started yet.", alert.AlertTypeWarning)
 	}
-	
+
 	// Write Notification status to indicate waiting for user
 	util.WriteStatusLog(selected.Id, "Notification", util.AiMuxDir)
-	
+
 	return tea.Batch(calcStatus(selected, 0, true), service.ResumeSession(selected))
 }
 
@@ -361,62 +576,136 @@ func (m *Model) closeSelected() tea.Cmd {
 		return nil
 	}
 
+	return func() tea.Msg {
+		onConfirm := func() tea.Msg {
+			return closeConfirmedMsg{item: selected}
+		}
+		return confirm.Confirm(
+			"Close Work Item",
+			fmt.Sprintf("Close %s?", selected.ShortName),
+			service.CloseSummary(selected),
+			"Close",
+			onConfirm,
+		)()
+	}
+}
+
+func (m *Model) closeItem(item *data.WorkItem) tea.Cmd {
 	// Write PrepStarting status
-	util.WriteStatusLog(selected.Id, "PrepForClosing", util.AiMuxDir)
+	util.WriteStatusLog(item.Id, "PrepForClosing", util.AiMuxDir)
+
+	return tea.Batch(calcStatus(item, 0, true), service.CloseSession(item))
+}
+
+func (m *Model) approveSelected() tea.Cmd {
+	selected := m.getSelected()
+	if selected == nil {
+		return toast.Toast("No work item selected", alert.AlertTypeWarning)
+	}
+	if !selected.IsWaiting() {
+		return toast.Toast("Claude is not waiting for input on this work item", alert.AlertTypeWarning)
+	}
 
-	return tea.Batch(calcStatus(selected, 0, true), service.CloseSession(selected))
+	return service.Approve(selected)
 }
 
 func (m *Model) openSelected() tea.Cmd {
 	selected := m.getSelected()
 	if selected == nil {
-		return alert.Alert("No work item selected", alert.AlertTypeWarning)
+		return toast.Toast("No work item selected", alert.AlertTypeWarning)
 	}
 
 	// Check if work item has been started (has a tmux window)
 	if selected.Status == "created" || selected.Status == "" {
-		return alert.Alert("Work item not started - no tmux window to switch to", alert.AlertTypeWarning)
-	}
-
-	// Determine session name (same logic as in service.go)
-	sessionName := ""
-	if !util.InTmuxSession() {
-		sessionName = "ai-mux"
+		return toast.Toast("Work item not started - no tmux window to switch to", alert.AlertTypeWarning)
 	}
 
 	// Switch to the tmux window
-	if err := util.SwitchToTmuxWindow(util.ToSafeName(selected.ShortName), sessionName); err != nil {
+	if err := util.SwitchToTmuxWindow(selected.WindowName, util.TmuxSessionName()); err != nil {
 		return alert.Alert(fmt.Sprintf("Failed to switch to tmux window: %v", err), alert.AlertTypeError)
 	}
 
 	return nil
 }
 
+// handleMouse selects the clicked item, opens its details on double click and moves the
+// selection with the scroll wheel
+func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress {
+		return nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.moveCursor(-1)
+	case tea.MouseButtonWheelDown:
+		m.moveCursor(1)
+	case tea.MouseButtonLeft:
+		r, ok := m.rowAt(msg.Y)
+		if !ok {
+			return nil
+		}
+		if r.item == nil {
+			// Clicking a group header folds or unfolds it
+			m.selectedGroup = r.group
+			m.toggleFold()
+			return nil
+		}
+		index := r.index
+		m.selectedGroup = ""
+		doubleClick := index == m.lastClickItem && time.Since(m.lastClick) < doubleClickInterval
+		m.selectedIndex = index
+		m.lastClickItem = index
+		m.lastClick = time.Now()
+		if doubleClick {
+			m.lastClick = time.Time{}
+			details := workitemdetails.New(m.workItems[index], m.workItems)
+			return tea.Sequence(modal.ShowModal(details, "Work Item Details"), details.Init())
+		}
+	}
+	return nil
+}
+
+// Selected returns the selected work item, nil when there is none
+func (m *Model) Selected() *data.WorkItem {
+	return m.getSelected()
+}
+
+// Items returns the open work items
+func (m *Model) Items() []*data.WorkItem {
+	return m.workItems
+}
+
 func (m *Model) getSelected() *data.WorkItem {
+	if m.selectedGroup != "" {
+		return nil
+	}
 	if m.selectedIndex >= 0 && m.selectedIndex < len(m.workItems) {
 		return m.workItems[m.selectedIndex]
 	}
 	return nil
 }
 
-func (m *Model) moveItemUp(index int) tea.Cmd {
-	if index <= 0 || index >= len(m.workItems) {
+// moveItem swaps the item at index with the one at other, which moves it past any items
+// of other groups in between, and keeps it selected
+func (m *Model) moveItem(index int, other int) tea.Cmd {
+	if index < 0 || index >= len(m.workItems) || other < 0 || other >= len(m.workItems) {
 		return nil
 	}
-	
+
 	// Swap the Order values
-	m.workItems[index].Order, m.workItems[index-1].Order = m.workItems[index-1].Order, m.workItems[index].Order
-	
+	m.workItems[index].Order, m.workItems[other].Order = m.workItems[other].Order, m.workItems[index].Order
+
 	// Create copies to save
 	item1 := *m.workItems[index]
-	item2 := *m.workItems[index-1]
-	
+	item2 := *m.workItems[other]
+
 	// Swap the items in the list
-	m.workItems[index], m.workItems[index-1] = m.workItems[index-1], m.workItems[index]
-	
+	m.workItems[index], m.workItems[other] = m.workItems[other], m.workItems[index]
+
 	// Move selection with the item
-	m.selectedIndex--
-	
+	m.selectedIndex = other
+
 	// Save both items
 	return tea.Batch(
 		func() tea.Msg {
@@ -434,39 +723,14 @@ func (m *Model) moveItemUp(index int) tea.Cmd {
 	)
 }
 
-func (m *Model) moveItemDown(index int) tea.Cmd {
-	if index < 0 || index >= len(m.workItems)-1 {
-		return nil
+// workItem returns the item of the list with the id, nil when it has been removed
+func (m *Model) workItem(id string) *data.WorkItem {
+	for _, item := range m.workItems {
+		if item.Id == id {
+			return item
+		}
 	}
-	
-	// Swap the Order values
-	m.workItems[index].Order, m.workItems[index+1].Order = m.workItems[index+1].Order, m.workItems[index].Order
-	
-	// Create copies to save
-	item1 := *m.workItems[index]
-	item2 := *m.workItems[index+1]
-	
-	// Swap the items in the list
-	m.workItems[index], m.workItems[index+1] = m.workItems[index+1], m.workItems[index]
-	
-	// Move selection with the item
-	m.selectedIndex++
-	
-	// Save both items
-	return tea.Batch(
-		func() tea.Msg {
-			if err := util.UpdateWorkItem(&item1); err != nil {
-				return alert.Alert(fmt.Sprintf("Failed to save item: %v", err), alert.AlertTypeError)()
-			}
-			return data.UpdateWorkItemMsg{WorkItem: &item1}
-		},
-		func() tea.Msg {
-			if err := util.UpdateWorkItem(&item2); err != nil {
-				return alert.Alert(fmt.Sprintf("Failed to save item: %v", err), alert.AlertTypeError)()
-			}
-			return data.UpdateWorkItemMsg{WorkItem: &item2}
-		},
-	)
+	return nil
 }
 
 func (m *Model) removeWorkItem(id string) {
@@ -484,6 +748,12 @@ func (m *Model) removeWorkItem(id string) {
 	}
 }
 
+// showAddForm opens the form for a new work item prefilled from item
+func (m *Model) showAddForm(item *data.WorkItem) tea.Cmd {
+	form := workform.New(item, m.workItems)
+	return tea.Batch(form.Init(), modal.ShowModal(form, "Add Work Item"))
+}
+
 func (m *Model) nextWorkItemOrder() int {
 	lastOrder := 0
 	for _, item := range m.workItems {
@@ -496,15 +766,26 @@ func (m *Model) nextWorkItemOrder() int {
 
 func New(width, height int) *Model {
 	return &Model{
-		width:    width,
-		height:   height,
-		viewport: viewport.New(width, height),
+		width:        width,
+		height:       height,
+		grouped:      config.Values.GroupByStatus,
+		compact:      config.Values.Compact,
+		folded:       map[string]bool{},
+		usage:        map[string]transcript.Usage{},
+		timing:       map[string]data.Timing{},
+		queuePaused:  config.Values.QueuePaused,
+		launched:     map[string]bool{},
+		merged:       map[string]bool{},
+		mergeChecked: map[string]time.Time{},
+		hookWarned:   map[string]bool{},
 	}
 }
 
 type statusUpdateMsg struct {
 	item    *data.WorkItem
 	status  string
+	timing  data.Timing
+	usage   transcript.Usage
 	oneTime bool
 }
 
@@ -512,34 +793,31 @@ func calcStatus(item *data.WorkItem, wait int, oneTime bool) tea.Cmd {
 	return func() tea.Msg {
 		time.Sleep(time.Duration(wait) * time.Second)
 
-		// Read the last line from status-log.txt
-		status := readLastStatus(item.Id)
+		status, timing := readStatus(item.Id)
+
+		// Errors reading the transcript are shown in the details, the row just shows no usage
+		usage, _ := transcript.ForItem(item)
 
 		return statusUpdateMsg{
 			item:    item,
 			status:  status,
+			timing:  timing,
+			usage:   usage,
 			oneTime: oneTime,
 		}
 	}
 }
 
-func readLastStatus(itemId string) string {
-	statusLogPath := filepath.Join(util.AiMuxDir, itemId, "state-log.txt")
-
-	content, err := os.ReadFile(statusLogPath)
+// readStatus returns the last status in the item's status log and the time spent in its states
+func readStatus(itemId string) (string, data.Timing) {
+	entries, err := util.ReadStatusLog(itemId)
 	if err != nil {
-		return "unknown"
+		return "unknown", data.Timing{}
 	}
-
-	// Split content into lines and get the last non-empty line
-	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
-	if len(lines) == 0 {
-		return ""
+	if len(entries) == 0 {
+		return "", data.Timing{}
 	}
-
-	lastLine := lines[len(lines)-1]
-	status := lastLine
-	return status
+	return entries[len(entries)-1].Status, data.TimingOf(entries)
 }
 
 func loadWorkItems() tea.Msg {
@@ -576,6 +854,11 @@ func loadWorkItems() tea.Msg {
 			continue
 		}
 
+		// Items saved before names were persisted get them filled in once
+		if service.MigrateNames(&item) {
+			util.UpdateWorkItem(&item)
+		}
+
 		items = append(items, &item)
 	}
 	sortItems(items)
@@ -589,6 +872,10 @@ func sortItems(items []*data.WorkItem) {
 	})
 }
 
+type closeConfirmedMsg struct {
+	item *data.WorkItem
+}
+
 type loadItemsMsg struct {
 	err   error
 	items []*data.WorkItem
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config holds the user settings read from .ai-mux/config.json
+type Config struct {
+	// SkipConfirmations runs destructive actions such as closing a work item without asking first
+	SkipConfirmations bool `json:"skipConfirmations"`
+
+	// Keys overrides key bindings, mapping a binding name such as "start" to its keys
+	Keys map[string][]string `json:"keys"`
+
+	// GroupByStatus starts the worklist grouped into waiting, working, done and not started
+	GroupByStatus bool `json:"groupByStatus"`
+
+	// Compact starts the worklist with one line per item
+	Compact bool `json:"compact"`
+
+	// WaitingAlertMinutes highlights items that have been waiting for input at least this
+	// long, 0 turns the highlight off
+	WaitingAlertMinutes int `json:"waitingAlertMinutes"`
+
+	// QueueLimit is how many agents the auto start queue lets work at once, 0 stops the queue
+	QueueLimit int `json:"queueLimit"`
+
+	// QueuePaused starts with the auto start queue paused
+	QueuePaused bool `json:"queuePaused"`
+
+	// Theme names a built in theme or a theme file in .ai-mux/themes
+	Theme string `json:"theme"`
+
+	// ControlKey is bound after the tmux prefix to jump back to ai-mux, empty leaves tmux alone
+	ControlKey string `json:"controlKey"`
+}
+
+// Values is the active configuration, defaults until Load is called
+var Values = Default()
+
+func Default() Config {
+	return Config{
+		WaitingAlertMinutes: 10,
+		QueueLimit:          2,
+		ControlKey:          "A",
+	}
+}
+
+// Load reads the config file at path over the defaults. A missing file is not an error.
+func Load(path string) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	loaded := Default()
+	if err := json.Unmarshal(content, &loaded); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	Values = loaded
+	return nil
+}
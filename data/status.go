@@ -0,0 +1,29 @@
+package data
+
+// IsStarted reports whether a session has been started for the item
+func (w *WorkItem) IsStarted() bool {
+	return w.Status != "created" && w.Status != ""
+}
+
+// IsWorking reports whether the agent is busy
+func (w *WorkItem) IsWorking() bool {
+	return isWorkingStatus(w.Status)
+}
+
+func isWorkingStatus(status string) bool {
+	switch status {
+	case "PreToolUse", "PostToolUse", "UserPromptSubmit", "Starting":
+		return true
+	}
+	return false
+}
+
+// IsWaiting reports whether the agent is waiting for input, such as a permission prompt
+func (w *WorkItem) IsWaiting() bool {
+	return w.Status == "Notification"
+}
+
+// IsDone reports whether the agent finished its turn
+func (w *WorkItem) IsDone() bool {
+	return w.Status == "Stop"
+}
@@ -0,0 +1,65 @@
+package data
+
+import "time"
+
+// StatusEntry is a line of a work item's status log. Time is zero for lines written
+// before the log recorded timestamps.
+type StatusEntry struct {
+	Status string
+	Time   time.Time
+}
+
+// Timing is how long a work item has spent in its states
+type Timing struct {
+	StateSince   time.Time     // when the current state began, zero when unknown
+	Active       time.Duration // time the agent spent working, up to the last status change
+	WorkingSince time.Time     // when the agent started its current turn, zero when it isn't working
+}
+
+// InState returns how long the item has been in its current state at now
+func (t Timing) InState(now time.Time) time.Duration {
+	if t.StateSince.IsZero() {
+		return 0
+	}
+	return now.Sub(t.StateSince)
+}
+
+// ActiveAt returns the total time the agent spent working at now
+func (t Timing) ActiveAt(now time.Time) time.Duration {
+	if t.WorkingSince.IsZero() {
+		return t.Active
+	}
+	return t.Active + now.Sub(t.WorkingSince)
+}
+
+// TimingOf works out the timing from a status log, oldest entry first. Working statuses
+// such as PreToolUse and PostToolUse count as one state.
+func TimingOf(entries []StatusEntry) Timing {
+	timing := Timing{}
+	for i, entry := range entries {
+		if entry.Time.IsZero() {
+			continue
+		}
+		if i == 0 || entries[i-1].Time.IsZero() || stateOf(entries[i-1].Status) != stateOf(entry.Status) {
+			timing.StateSince = entry.Time
+		}
+		if i > 0 && isWorkingStatus(entries[i-1].Status) && !entries[i-1].Time.IsZero() {
+			timing.Active += entry.Time.Sub(entries[i-1].Time)
+		}
+	}
+
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		if isWorkingStatus(last.Status) {
+			timing.WorkingSince = last.Time
+		}
+	}
+	return timing
+}
+
+func stateOf(status string) string {
+	if isWorkingStatus(status) {
+		return "working"
+	}
+	return status
+}
@@ -1,14 +1,41 @@
 package data
 
 type WorkItem struct {
-	Id          string
-	ShortName   string
-	Description string
-	Order       int
-	Status      string
-	IsClosing   bool
+	Id              string
+	ShortName       string
+	Description     string
+	Tags            []string
+	StartMode       string // permission mode the queue starts the session in, "default" when empty
+	Options         StartOptions
+	AutoStart       bool     // started by the queue once enough agents are free
+	DependsOn       []string // ids of items that must be done, merged or removed before this one starts
+	BaseOn          string   // id of the prerequisite whose branch the new branch is created from, HEAD when empty
+	Layout          string   // arrangement of the tmux window, LayoutStacked when empty
+	Order           int
+	Status          string
+	IsClosing       bool
+	BranchName      string // git branch, fixed at creation so editing ShortName does not orphan it
+	WorktreePath    string // git worktree folder, fixed at creation
+	AdoptedWorktree bool   // the worktree existed before the item was imported and is left in place on close
+	WindowName      string // tmux window, fixed at creation
 }
 
+// StartOptions adjust the claude command a work item's session is started and resumed with
+type StartOptions struct {
+	Model           string            `json:",omitempty"` // Claude's default when empty
+	AllowedTools    []string          `json:",omitempty"` // tools allowed without asking, like Bash(go test:*)
+	DisallowedTools []string          `json:",omitempty"`
+	ExtraArgs       string            `json:",omitempty"` // passed to claude as typed
+	Env             map[string]string `json:",omitempty"`
+}
+
+// Layouts of a work item's tmux window
+const (
+	LayoutStacked    = "stacked"      // editor above Claude
+	LayoutSideBySide = "side-by-side" // editor left of Claude
+	LayoutClaude     = "claude"       // Claude alone
+)
+
 type NewWorkItemMsg struct {
 	WorkItem *WorkItem
 }
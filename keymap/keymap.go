@@ -0,0 +1,375 @@
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds every configurable key binding. The help text of each binding is the
+// short label shown in the footer, longer descriptions for the help modal are in Groups.
+type KeyMap struct {
+	ForceQuit key.Binding
+	Quit      key.Binding
+	Help      key.Binding
+	Messages  key.Binding
+	Find      key.Binding
+	Commands  key.Binding
+	Usage     key.Binding
+
+	Up       key.Binding
+	Down     key.Binding
+	MoveUp   key.Binding
+	MoveDown key.Binding
+	Group    key.Binding
+	Fold     key.Binding
+	Density  key.Binding
+
+	Add      key.Binding
+	Import   key.Binding
+	Edit     key.Binding
+	Rename   key.Binding
+	Template key.Binding
+	Details  key.Binding
+	Close    key.Binding
+
+	Start     key.Binding
+	StartWith key.Binding
+	Plan      key.Binding
+	Vibe      key.Binding
+	Resume    key.Binding
+	Open      key.Binding
+	Approve   key.Binding
+	Queue     key.Binding
+
+	CloseModal key.Binding
+	Editor     key.Binding
+	Transcript key.Binding
+
+	// Confirmations
+	Yes          key.Binding
+	No           key.Binding
+	SwitchButton key.Binding
+	Select       key.Binding // also runs the match in the finder and a transcript search
+
+	// Finder
+	FinderUp      key.Binding
+	FinderDown    key.Binding
+	FinderActions key.Binding
+
+	// Transcript, which moves with Up and Down
+	TranscriptTop      key.Binding
+	TranscriptBottom   key.Binding
+	TranscriptFold     key.Binding
+	TranscriptOpenAll  key.Binding
+	TranscriptCloseAll key.Binding
+	TranscriptSearch   key.Binding
+	TranscriptNext     key.Binding
+	TranscriptPrevious key.Binding
+	PageDown           key.Binding
+	PageUp             key.Binding
+}
+
+// Keys is the active key map, the defaults until Load is called
+var Keys = Default()
+
+func Default() KeyMap {
+	return KeyMap{
+		ForceQuit: binding("Force quit", "ctrl+c"),
+		Quit:      binding("Quit", "q"),
+		Help:      binding("Help", "?"),
+		Messages:  binding("Messages", "L"),
+		Find:      binding("Find", "/"),
+		Commands:  binding("Commands", ":"),
+		Usage:     binding("Usage", "U"),
+
+		Up:       binding("Up", "k", "up"),
+		Down:     binding("Down", "j", "down"),
+		MoveUp:   binding("Move up", "ctrl+k"),
+		MoveDown: binding("Move down", "ctrl+j"),
+		Group:    binding("Group", "g"),
+		Fold:     binding("Fold", " "),
+		Density:  binding("Density", "d"),
+
+		Add:      binding("Add", "a"),
+		Import:   binding("Import", "i"),
+		Edit:     binding("Edit", "e"),
+		Rename:   binding("Rename", "R"),
+		Template: binding("Save template", "T"),
+		Details:  binding("Info", "enter"),
+		Close:    binding("Close", "c"),
+
+		Start:     binding("Start", "s"),
+		StartWith: binding("Start with options", "S"),
+		Plan:      binding("Plan", "p"),
+		Vibe:      binding("Vibe", "v"),
+		Resume:    binding("Resume", "r"),
+		Open:      binding("Open", "o"),
+		Approve:   binding("Approve", "y"),
+		Queue:     binding("Queue", "Q"),
+
+		CloseModal: binding("Close dialog", "esc"),
+		Editor:     binding("Editor", "ctrl+e"),
+		Transcript: binding("Transcript", "t"),
+
+		Yes:          binding("Yes", "y"),
+		No:           binding("No", "n"),
+		SwitchButton: binding("Switch button", "left", "right", "h", "l", "tab", "shift+tab"),
+		Select:       binding("Select", "enter"),
+
+		FinderUp:      binding("Up", "up", "ctrl+p", "ctrl+k"),
+		FinderDown:    binding("Down", "down", "ctrl+n", "ctrl+j"),
+		FinderActions: binding("Actions", "tab"),
+
+		TranscriptTop:      binding("Top", "g", "home"),
+		TranscriptBottom:   binding("Bottom", "G", "end"),
+		TranscriptFold:     binding("Fold", "enter", " "),
+		TranscriptOpenAll:  binding("Open all", "o"),
+		TranscriptCloseAll: binding("Close all", "c"),
+		TranscriptSearch:   binding("Search", "/"),
+		TranscriptNext:     binding("Next", "n"),
+		TranscriptPrevious: binding("Previous", "N"),
+		PageDown:           binding("Page down", "ctrl+d", "pgdown"),
+		PageUp:             binding("Page up", "ctrl+u", "pgup"),
+	}
+}
+
+func binding(label string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(DisplayKeys(keys), label))
+}
+
+// DisplayKeys formats keys the way they are shown in the footer and help
+func DisplayKeys(keys []string) string {
+	display := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			display[i] = "↑"
+		case "down":
+			display[i] = "↓"
+		case "enter":
+			display[i] = "Enter"
+		case "esc":
+			display[i] = "Esc"
+		case "left":
+			display[i] = "←"
+		case "right":
+			display[i] = "→"
+		case " ":
+			display[i] = "Space"
+		default:
+			display[i] = k
+		}
+	}
+	return strings.Join(display, "/")
+}
+
+// Hint formats bindings as a line of key hints for the bottom of a dialog
+func Hint(bindings ...key.Binding) string {
+	hints := make([]string, len(bindings))
+	for i, b := range bindings {
+		hints[i] = b.Help().Key + ": " + strings.ToLower(b.Help().Desc)
+	}
+	return strings.Join(hints, " · ")
+}
+
+// Entry is a binding with the description shown for it in the help modal
+type Entry struct {
+	Binding     key.Binding
+	Description string
+}
+
+// Group is a titled section of the help modal
+type Group struct {
+	Title   string
+	Entries []Entry
+}
+
+// Groups lists the bindings by section for the help modal
+func (k KeyMap) Groups() []Group {
+	return []Group{
+		{"General", []Entry{
+			{k.Quit, "Quit application"},
+			{k.Help, "Show this help"},
+			{k.Messages, "Show the log of past messages"},
+			{k.Find, "Find a work item by name, description, tag or branch (tab lists its actions)"},
+			{k.Commands, "List the actions available on the selected work item"},
+			{k.Usage, "Show token usage and estimated cost of all work items"},
+			{k.Down, "Move selection down"},
+			{k.Up, "Move selection up"},
+			{k.MoveDown, "Move work item down"},
+			{k.MoveUp, "Move work item up"},
+			{k.Group, "Group work items by status"},
+			{k.Fold, "Fold or unfold the selected group"},
+			{k.Density, "Switch between full and compact one line rows"},
+			{k.CloseModal, "Close modal/dialog"},
+		}},
+		{"Work Items", []Entry{
+			{k.Add, "Add new work item, from a template when there are any"},
+			{k.Import, "Import an existing branch or worktree as a work item"},
+			{k.Edit, "Edit work item"},
+			{k.Editor, "Write the description in $EDITOR (in the work item form)"},
+			{k.Rename, "Rename work item along with its branch and tmux window"},
+			{k.Template, "Save work item as a template for new ones"},
+			{k.Details, "Show work item details inlcuding code changes made"},
+			{k.Transcript, "Read the Claude conversation (in the work item details)"},
+			{k.Close, "Close work item"},
+		}},
+		{"Session Management", []Entry{
+			{k.Start, "Start session in default mode (manual accept)"},
+			{k.StartWith, "Choose the model, permission mode, tools, arguments and environment, then start"},
+			{k.Plan, "Start session in plan mode"},
+			{k.Vibe, "Start session in vibe/accept-edits mode"},
+			{k.Resume, "Resume existing session (in case a claude session was interrupted)"},
+			{k.Open, "Open/switch to tmux window"},
+			{k.Approve, "Approve the prompt Claude is waiting on"},
+			{k.Queue, "Pause or resume starting queued work items automatically"},
+		}},
+		{"Dialogs", []Entry{
+			{k.Yes, "Confirm, like closing a work item"},
+			{k.No, "Cancel the confirmation"},
+			{k.SwitchButton, "Move between the buttons of a confirmation"},
+			{k.Select, "Press the focused button, run the chosen match in the finder or search the transcript"},
+			{k.FinderUp, "Move to the previous match in the finder"},
+			{k.FinderDown, "Move to the next match in the finder"},
+			{k.FinderActions, "Switch the finder between work items and the actions of one"},
+		}},
+		{"Transcript", []Entry{
+			{k.TranscriptTop, "Go to the first message"},
+			{k.TranscriptBottom, "Go to the last message"},
+			{k.TranscriptFold, "Fold or unfold the selected message"},
+			{k.TranscriptOpenAll, "Unfold every message"},
+			{k.TranscriptCloseAll, "Fold every message"},
+			{k.TranscriptSearch, "Search the messages"},
+			{k.TranscriptNext, "Go to the next match of the search"},
+			{k.TranscriptPrevious, "Go to the previous match of the search"},
+			{k.PageDown, "Scroll half a page down"},
+			{k.PageUp, "Scroll half a page up"},
+		}},
+	}
+}
+
+// named returns the bindings by the name used for them in the config file
+func (k *KeyMap) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"forceQuit":  &k.ForceQuit,
+		"quit":       &k.Quit,
+		"help":       &k.Help,
+		"messages":   &k.Messages,
+		"find":       &k.Find,
+		"commands":   &k.Commands,
+		"usage":      &k.Usage,
+		"up":         &k.Up,
+		"down":       &k.Down,
+		"moveUp":     &k.MoveUp,
+		"moveDown":   &k.MoveDown,
+		"group":      &k.Group,
+		"fold":       &k.Fold,
+		"density":    &k.Density,
+		"add":        &k.Add,
+		"import":     &k.Import,
+		"edit":       &k.Edit,
+		"rename":     &k.Rename,
+		"template":   &k.Template,
+		"details":    &k.Details,
+		"close":      &k.Close,
+		"start":      &k.Start,
+		"startWith":  &k.StartWith,
+		"plan":       &k.Plan,
+		"vibe":       &k.Vibe,
+		"resume":     &k.Resume,
+		"open":       &k.Open,
+		"approve":    &k.Approve,
+		"queue":      &k.Queue,
+		"closeModal": &k.CloseModal,
+		"editor":     &k.Editor,
+		"transcript": &k.Transcript,
+
+		"yes":                &k.Yes,
+		"no":                 &k.No,
+		"switchButton":       &k.SwitchButton,
+		"select":             &k.Select,
+		"finderUp":           &k.FinderUp,
+		"finderDown":         &k.FinderDown,
+		"finderActions":      &k.FinderActions,
+		"transcriptTop":      &k.TranscriptTop,
+		"transcriptBottom":   &k.TranscriptBottom,
+		"transcriptFold":     &k.TranscriptFold,
+		"transcriptOpenAll":  &k.TranscriptOpenAll,
+		"transcriptCloseAll": &k.TranscriptCloseAll,
+		"transcriptSearch":   &k.TranscriptSearch,
+		"transcriptNext":     &k.TranscriptNext,
+		"transcriptPrevious": &k.TranscriptPrevious,
+		"pageDown":           &k.PageDown,
+		"pageUp":             &k.PageUp,
+	}
+}
+
+// scopes lists the bindings that are active at the same time and so must not share keys
+func (k *KeyMap) scopes() map[string][]string {
+	return map[string][]string{
+		"main view": {
+			"forceQuit", "quit", "help", "messages", "find", "commands", "usage", "up", "down", "moveUp", "moveDown", "group", "fold", "density",
+			"add", "import", "edit", "rename", "template", "details", "close",
+			"start", "startWith", "plan", "vibe", "resume", "open", "approve", "queue",
+		},
+		"dialogs":      {"forceQuit", "closeModal", "editor"},
+		"details":      {"forceQuit", "closeModal", "transcript"},
+		"confirmation": {"forceQuit", "closeModal", "yes", "no", "switchButton", "select"},
+		"finder":       {"forceQuit", "closeModal", "finderUp", "finderDown", "finderActions", "select"},
+		"transcript": {
+			"forceQuit", "closeModal", "up", "down", "transcriptTop", "transcriptBottom", "transcriptFold",
+			"transcriptOpenAll", "transcriptCloseAll", "transcriptSearch", "transcriptNext", "transcriptPrevious", "pageDown", "pageUp",
+		},
+	}
+}
+
+// Load applies the key overrides from the config, keyed by binding name, over the
+// defaults and makes the result the active key map
+func Load(overrides map[string][]string) error {
+	k := Default()
+	named := k.named()
+	for name, keys := range overrides {
+		b, ok := named[name]
+		if !ok {
+			return fmt.Errorf("unknown key binding %q", name)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("key binding %q has no keys", name)
+		}
+		*b = binding(b.Help().Desc, keys...)
+	}
+
+	if err := k.Conflicts(); err != nil {
+		return err
+	}
+	Keys = k
+	return nil
+}
+
+// Conflicts reports keys bound to more than one action in the same scope
+func (k KeyMap) Conflicts() error {
+	named := k.named()
+	conflicts := []string{}
+	for scope, names := range k.scopes() {
+		owners := map[string][]string{}
+		for _, name := range names {
+			for _, key := range named[name].Keys() {
+				owners[key] = append(owners[key], name)
+			}
+		}
+		for key, names := range owners {
+			if len(names) > 1 {
+				conflicts = append(conflicts, fmt.Sprintf("%q is bound to %s in the %s", key, strings.Join(names, " and "), scope))
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("conflicting key bindings: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
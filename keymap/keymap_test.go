@@ -0,0 +1,84 @@
+package keymap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:      "free key",
+			overrides: map[string][]string{"quit": {"x"}},
+		},
+		{
+			name:      "same key in different scopes",
+			overrides: map[string][]string{"yes": {"a"}, "transcriptOpenAll": {"s"}},
+		},
+		{
+			name:      "key taken in the main view",
+			overrides: map[string][]string{"quit": {"a"}},
+			wantErr:   `conflicting key bindings: "a" is bound to quit and add in the main view`,
+		},
+		{
+			name:      "key taken in a modal",
+			overrides: map[string][]string{"transcriptSearch": {"n"}},
+			wantErr:   `conflicting key bindings: "n" is bound to transcriptSearch and transcriptNext in the transcript`,
+		},
+		{
+			name:      "binding shared between scopes conflicts in each",
+			overrides: map[string][]string{"up": {"g"}},
+			wantErr: `conflicting key bindings: "g" is bound to up and group in the main view; ` +
+				`"g" is bound to up and transcriptTop in the transcript`,
+		},
+		{
+			name:      "every conflict is listed",
+			overrides: map[string][]string{"closeModal": {"enter"}},
+			wantErr: `conflicting key bindings: "enter" is bound to closeModal and select in the confirmation; ` +
+				`"enter" is bound to closeModal and select in the finder; ` +
+				`"enter" is bound to closeModal and transcriptFold in the transcript`,
+		},
+		{
+			name:      "unknown binding",
+			overrides: map[string][]string{"launch": {"x"}},
+			wantErr:   `unknown key binding "launch"`,
+		},
+		{
+			name:      "binding without keys",
+			overrides: map[string][]string{"quit": {}},
+			wantErr:   `key binding "quit" has no keys`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { Keys = Default() })
+
+			err := Load(tt.overrides)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() failed: %v", err)
+				}
+				for name, keys := range tt.overrides {
+					if got := Keys.named()[name].Keys(); !slices.Equal(got, keys) {
+						t.Errorf("after Load() %s is bound to %v, want %v", name, got, keys)
+					}
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Load() error = %v, want %s", err, tt.wantErr)
+			}
+			if Keys.Quit.Keys()[0] != "q" {
+				t.Errorf("a failed Load() changed the active keys")
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/jquag/ai-mux/config"
+	"github.com/jquag/ai-mux/util"
+)
+
+const (
+	// controlWindow is the window of the control session the launcher runs ai-mux in
+	controlWindow = "ai-mux"
+	// controlEnv is set in the control window so ai-mux started there runs the TUI itself
+	controlEnv = "AI_MUX_CONTROL"
+)
+
+
+// shouldLaunch reports whether ai-mux should move into its control window rather than run
+// in the current terminal
+func shouldLaunch(args []string) bool {
+	for _, arg := range args {
+		if arg == "--inline" {
+			return false
+		}
+	}
+	return os.Getenv(controlEnv) == ""
+}
+
+// launchControlWindow runs ai-mux with args in the first window of the repository's control
+// session, creating the session or window as needed, and brings the terminal to it: by
+// attaching from outside tmux, or by switching the client when already attached to another
+// session. The window stays open when ai-mux fails so its error can be read.
+func launchControlWindow(args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the ai-mux executable: %w", err)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	parts := []string{"env", controlEnv + "=1", util.ShellQuote(executable)}
+	for _, arg := range args {
+		parts = append(parts, util.ShellQuote(arg))
+	}
+	command := strings.Join(parts, " ") + " || { echo 'Press enter to close'; read -r _; }"
+
+	controlSession := util.ControlSessionName(dir)
+	target := util.WindowTarget(controlWindow, controlSession)
+	if exec.Command("tmux", "has-session", "-t", controlSession).Run() != nil {
+		cmd := exec.Command("tmux", "new-session", "-d", "-s", controlSession, "-n", controlWindow, "-c", dir, command)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to create tmux session '%s': %w - output: %s", controlSession, err, string(output))
+		}
+		if err := util.SetSessionDir(controlSession, dir); err != nil {
+			return err
+		}
+	} else if err := util.CheckSessionDir(controlSession, dir); err != nil {
+		return err
+	} else if !util.WindowExists(controlWindow, controlSession) {
+		// Take the first window when it is free, the session may hold item windows already
+		cmd := exec.Command("tmux", "new-window", "-d", "-t", controlSession+":0", "-n", controlWindow, "-c", dir, command)
+		if cmd.Run() != nil {
+			cmd = exec.Command("tmux", "new-window", "-d", "-t", controlSession+":", "-n", controlWindow, "-c", dir, command)
+			if output, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("failed to create the ai-mux window: %w - output: %s", err, string(output))
+			}
+		}
+	}
+
+	if err := exec.Command("tmux", "select-window", "-t", target).Run(); err != nil {
+		return fmt.Errorf("failed to select the ai-mux window: %w", err)
+	}
+	if util.InTmuxSession() {
+		if output, err := exec.Command("tmux", "switch-client", "-t", controlSession).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to switch to tmux session '%s': %w - output: %s", controlSession, err, string(output))
+		}
+		return nil
+	}
+
+	tmux, err := exec.LookPath("tmux")
+	if err != nil {
+		return err
+	}
+	// Hand the terminal over to tmux for good
+	return syscall.Exec(tmux, []string{"tmux", "attach-session", "-t", controlSession}, os.Environ())
+}
+
+// bindControlKey binds the configured key, after the tmux prefix, to jump back to the pane
+// ai-mux runs in from any window. It returns a function putting back the binding the key
+// had before, unless another ai-mux has bound the key since.
+func bindControlKey() func() {
+	pane := os.Getenv("TMUX_PANE")
+	key := config.Values.ControlKey
+	if pane == "" || key == "" {
+		return func() {}
+	}
+
+	output, err := exec.Command("tmux", "display-message", "-p", "-t", pane, "#{session_id} #{window_id}").Output()
+	fields := strings.Fields(string(output))
+	if err != nil || len(fields) != 2 {
+		return func() {}
+	}
+	jump := fmt.Sprintf("switch-client -t %s ; select-window -t %s ; select-pane -t %s", fields[0], fields[1], pane)
+	previous := keyBinding(key)
+	err = exec.Command("tmux", "bind-key", key, jump).Run()
+	if err != nil {
+		return func() {}
+	}
+	ours := keyBinding(key)
+	return func() {
+		if keyBinding(key) != ours {
+			return
+		}
+		if previous == "" {
+			exec.Command("tmux", "unbind-key", key).Run()
+			return
+		}
+		// list-keys prints the binding as the command making it
+		restore := exec.Command("tmux", "source-file", "-")
+		restore.Stdin = strings.NewReader(previous)
+		restore.Run()
+	}
+}
+
+// keyBinding returns the binding of key after the prefix as tmux lists it, empty when unbound
+func keyBinding(key string) string {
+	output, err := exec.Command("tmux", "list-keys", "-T", "prefix", key).Output()
+	if err != nil {
+		return ""
+	}
+	return string(output)
+}
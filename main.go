@@ -1,7 +1,6 @@
 package main
 
 import (
-	_ "embed"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,12 +10,13 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jquag/ai-mux/component/app"
+	"github.com/jquag/ai-mux/config"
+	"github.com/jquag/ai-mux/keymap"
+	"github.com/jquag/ai-mux/settings"
+	"github.com/jquag/ai-mux/theme"
 	"github.com/jquag/ai-mux/util"
 )
 
-//go:embed claudeSettings.json
-var claudeSettings string
-
 
 func checkCommand(name string) error {
 	_, err := exec.LookPath(name)
@@ -78,12 +78,28 @@ func main() {
 		os.Exit(1)
 	}
 	
-	settingsPath := filepath.Join(util.AiMuxDir, "claude-settings.json")
-	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
-		if err := os.WriteFile(settingsPath, []byte(claudeSettings), 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing claude-settings.json: %v\n", err)
-			os.Exit(1)
-		}
+	if err := settings.EnsureBase(util.AiMuxDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := config.Load(filepath.Join(util.AiMuxDir, "config.json")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := keymap.Load(config.Values.Keys); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in config.json: %v\n", err)
+		os.Exit(1)
+	}
+
+	themeDirs := []string{filepath.Join(util.AiMuxDir, "themes")}
+	if dir, err := os.UserConfigDir(); err == nil {
+		themeDirs = append(themeDirs, filepath.Join(dir, "ai-mux", "themes"))
+	}
+	if err := theme.Load(config.Values.Theme, themeDirs...); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in config.json: %v\n", err)
+		os.Exit(1)
 	}
 
 	if err := checkSystemRequirements(); err != nil {
@@ -91,11 +107,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	if shouldLaunch(os.Args[1:]) {
+		if err := launchControlWindow(os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	unbind := bindControlKey()
+
 	model := app.New()
 
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("There's been an error: %v", err)
+	_, err := p.Run()
+	unbind()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "There's been an error: %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/util"
+)
+
+// PermissionModes are the permission modes a session can be started in
+var PermissionModes = []string{"default", "plan", "acceptEdits", "bypassPermissions"}
+
+// claudeCommand builds the shell command starting the item's Claude session in mode, or
+// resuming it when mode is "resume", with the item's start options merged in
+func claudeCommand(workitem *data.WorkItem, mode string, aiMuxDirPath string, settingsPath string) string {
+	options := workitem.Options
+	parts := []string{"AI_MUX_DIR=" + util.ShellQuote(aiMuxDirPath)}
+
+	envKeys := make([]string, 0, len(options.Env))
+	for name := range options.Env {
+		envKeys = append(envKeys, name)
+	}
+	sort.Strings(envKeys)
+	for _, name := range envKeys {
+		parts = append(parts, name+"="+util.ShellQuote(options.Env[name]))
+	}
+
+	parts = append(parts, "claude")
+	// Extra arguments come first so a flag taking several values can't swallow the prompt
+	if extra := strings.TrimSpace(options.ExtraArgs); extra != "" {
+		parts = append(parts, extra)
+	}
+	if options.Model != "" {
+		parts = append(parts, "--model", util.ShellQuote(options.Model))
+	}
+	// The tool flags take any number of values, so they must be followed by another flag
+	// and not the prompt
+	if len(options.AllowedTools) > 0 {
+		parts = append(parts, "--allowedTools")
+		parts = append(parts, quoteAll(options.AllowedTools)...)
+	}
+	if len(options.DisallowedTools) > 0 {
+		parts = append(parts, "--disallowedTools")
+		parts = append(parts, quoteAll(options.DisallowedTools)...)
+	}
+
+	if mode == "resume" {
+		// For resume, use --resume instead of --session-id and don't pass the prompt
+		parts = append(parts, "--resume", workitem.Id, "--settings", util.ShellQuote(settingsPath))
+	} else {
+		parts = append(parts, "--session-id", workitem.Id, "--settings", util.ShellQuote(settingsPath), "--permission-mode", mode,
+			util.ShellQuote(workitem.Description))
+	}
+	return strings.Join(parts, " ")
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = util.ShellQuote(value)
+	}
+	return quoted
+}
+
+// ValidateEnvName checks that name can be set as an environment variable in the shell
+func ValidateEnvName(name string) error {
+	for i, r := range name {
+		if r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return fmt.Errorf("%q is not a valid environment variable name", name)
+	}
+	if name == "" {
+		return fmt.Errorf("environment variable name is required")
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jquag/ai-mux/data"
+)
+
+func TestClaudeCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    data.WorkItem
+		mode    string
+		dir     string // the ai-mux directory, /repo/.ai-mux when empty
+		want    string
+		wantArg []string // arguments claude gets once the shell has parsed the command
+	}{
+		{
+			name: "start",
+			item: data.WorkItem{Id: "id-1", Description: "fix the bug"},
+			mode: "plan",
+			want: "AI_MUX_DIR='/repo/.ai-mux' claude --session-id id-1 --settings '/repo/.ai-mux/id-1/s.json' --permission-mode plan 'fix the bug'",
+			wantArg: []string{
+				"--session-id", "id-1", "--settings", "/repo/.ai-mux/id-1/s.json", "--permission-mode", "plan", "fix the bug",
+			},
+		},
+		{
+			name: "resume leaves out the prompt and mode",
+			item: data.WorkItem{Id: "id-1", Description: "fix the bug"},
+			mode: "resume",
+			want: "AI_MUX_DIR='/repo/.ai-mux' claude --resume id-1 --settings '/repo/.ai-mux/id-1/s.json'",
+			wantArg: []string{
+				"--resume", "id-1", "--settings", "/repo/.ai-mux/id-1/s.json",
+			},
+		},
+		{
+			name: "prompt with quotes and shell syntax",
+			item: data.WorkItem{Id: "id-1", Description: "don't run $(rm -rf /) or `ls`;\necho \"done\""},
+			mode: "default",
+			want: "AI_MUX_DIR='/repo/.ai-mux' claude --session-id id-1 --settings '/repo/.ai-mux/id-1/s.json' --permission-mode default " +
+				"'don'\\''t run $(rm -rf /) or `ls`;\necho \"done\"'",
+			wantArg: []string{
+				"--session-id", "id-1", "--settings", "/repo/.ai-mux/id-1/s.json", "--permission-mode", "default",
+				"don't run $(rm -rf /) or `ls`;\necho \"done\"",
+			},
+		},
+		{
+			name: "every option, extra arguments first",
+			item: data.WorkItem{Id: "id-1", Description: "go", Options: data.StartOptions{
+				Model:           "claude-sonnet",
+				AllowedTools:    []string{"Bash(go test:*)", "Read"},
+				DisallowedTools: []string{"WebFetch"},
+				ExtraArgs:       " --verbose --add-dir ../shared ",
+				Env:             map[string]string{"ZED": "last", "DEBUG": "it's on"},
+			}},
+			mode: "acceptEdits",
+			want: "AI_MUX_DIR='/repo/.ai-mux' DEBUG='it'\\''s on' ZED='last' claude --verbose --add-dir ../shared --model 'claude-sonnet' " +
+				"--allowedTools 'Bash(go test:*)' 'Read' --disallowedTools 'WebFetch' " +
+				"--session-id id-1 --settings '/repo/.ai-mux/id-1/s.json' --permission-mode acceptEdits 'go'",
+			wantArg: []string{
+				"--verbose", "--add-dir", "../shared", "--model", "claude-sonnet",
+				"--allowedTools", "Bash(go test:*)", "Read", "--disallowedTools", "WebFetch",
+				"--session-id", "id-1", "--settings", "/repo/.ai-mux/id-1/s.json", "--permission-mode", "acceptEdits", "go",
+			},
+		},
+		{
+			name: "resume keeps the options",
+			item: data.WorkItem{Id: "id-1", Options: data.StartOptions{Model: "opus", ExtraArgs: "--verbose"}},
+			mode: "resume",
+			want: "AI_MUX_DIR='/repo/.ai-mux' claude --verbose --model 'opus' --resume id-1 --settings '/repo/.ai-mux/id-1/s.json'",
+			wantArg: []string{
+				"--verbose", "--model", "opus", "--resume", "id-1", "--settings", "/repo/.ai-mux/id-1/s.json",
+			},
+		},
+		{
+			name: "paths with spaces",
+			item: data.WorkItem{Id: "id-1", Description: "go"},
+			mode: "default",
+			dir:  "/my repo/.ai-mux",
+			want: "AI_MUX_DIR='/my repo/.ai-mux' claude --session-id id-1 --settings '/my repo/.ai-mux/id-1/s.json' --permission-mode default 'go'",
+			wantArg: []string{
+				"--session-id", "id-1", "--settings", "/my repo/.ai-mux/id-1/s.json", "--permission-mode", "default", "go",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tt.dir
+			if dir == "" {
+				dir = "/repo/.ai-mux"
+			}
+			got := claudeCommand(&tt.item, tt.mode, dir, dir+"/id-1/s.json")
+			if got != tt.want {
+				t.Errorf("claudeCommand()\n got: %s\nwant: %s", got, tt.want)
+			}
+
+			// Run it with claude standing in for a function printing its arguments
+			output, err := exec.Command("sh", "-c", `claude() { printf '%s\0' "$@"; }; `+got).Output()
+			if err != nil {
+				t.Fatalf("running %s: %v", got, err)
+			}
+			args := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+			if strings.Join(args, "|") != strings.Join(tt.wantArg, "|") {
+				t.Errorf("claude got arguments\n%q\nwant\n%q", args, tt.wantArg)
+			}
+		})
+	}
+}
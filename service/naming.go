@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jquag/ai-mux/component/alert"
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/util"
+)
+
+// AssignNames fixes the git branch, worktree folder and tmux window of a new work item.
+// They are derived from the short name once and persisted, so editing the short name
+// later does not lose track of the session. Imported items keep their branch and worktree.
+func AssignNames(workitem *data.WorkItem, others []*data.WorkItem) error {
+	if workitem.BranchName == "" {
+		name := util.UniqueName(util.ToSafeName(workitem.ShortName), func(name string) bool {
+			return nameTaken(name, workitem, others) || util.BranchExists(name) || worktreeTaken(name)
+		})
+		workitem.BranchName = name
+		workitem.WindowName = name
+	} else {
+		workitem.WindowName = util.UniqueName(util.ToSafeName(workitem.BranchName), func(name string) bool {
+			return nameTaken(name, workitem, others)
+		})
+	}
+
+	if workitem.WorktreePath == "" {
+		name := util.UniqueName(util.ToSafeName(workitem.BranchName), worktreeTaken)
+		worktreePath, err := util.DefaultWorktreePath(name)
+		if err != nil {
+			return err
+		}
+		workitem.WorktreePath = worktreePath
+	}
+	return nil
+}
+
+// MigrateNames fills in the names of work items saved before they were persisted, using the
+// derivation those items were started with. It reports whether the item was changed.
+func MigrateNames(workitem *data.WorkItem) bool {
+	if workitem.BranchName != "" && workitem.WindowName != "" && workitem.WorktreePath != "" {
+		return false
+	}
+
+	legacyName := strings.ReplaceAll(workitem.ShortName, " ", "-")
+	if workitem.BranchName == "" {
+		workitem.BranchName = legacyName
+	}
+	if workitem.WindowName == "" {
+		workitem.WindowName = legacyName
+	}
+	if workitem.WorktreePath == "" {
+		if worktreePath, err := util.DefaultWorktreePath(workitem.BranchName); err == nil {
+			workitem.WorktreePath = worktreePath
+		}
+	}
+	return true
+}
+
+// RenameWorkItem changes the short name of a work item and moves its branch and tmux window
+// along with it. The worktree folder stays put once started since the agent is running in it.
+func RenameWorkItem(workitem *data.WorkItem, shortName string, others []*data.WorkItem) tea.Cmd {
+	return func() tea.Msg {
+		renamed := *workitem
+		renamed.ShortName = shortName
+
+		name := util.ToSafeName(shortName)
+		if name != workitem.BranchName {
+			name = util.UniqueName(name, func(name string) bool {
+				return nameTaken(name, workitem, others) || util.BranchExists(name) || worktreeTaken(name)
+			})
+		}
+
+		isStarted := workitem.Status != "created" && workitem.Status != ""
+		if util.BranchExists(workitem.BranchName) && name != workitem.BranchName {
+			if err := util.RenameBranch(workitem.BranchName, name); err != nil {
+				return alert.Alert(err.Error(), alert.AlertTypeError)()
+			}
+		}
+		sessionName := util.TmuxSessionName()
+		if util.WindowExists(workitem.WindowName, sessionName) && name != workitem.WindowName {
+			if err := util.RenameTmuxWindow(workitem.WindowName, sessionName, name); err != nil {
+				return alert.Alert(err.Error(), alert.AlertTypeError)()
+			}
+		}
+		renamed.BranchName = name
+		renamed.WindowName = name
+		// Follow the new name only with the folder ai-mux picked, an imported item keeps the
+		// worktree it was bound to
+		oldWorktreePath, err := util.DefaultWorktreePath(workitem.BranchName)
+		if err != nil {
+			return alert.Alert(err.Error(), alert.AlertTypeError)()
+		}
+		if !isStarted && workitem.WorktreePath == oldWorktreePath {
+			worktreePath, err := util.DefaultWorktreePath(name)
+			if err != nil {
+				return alert.Alert(err.Error(), alert.AlertTypeError)()
+			}
+			renamed.WorktreePath = worktreePath
+		}
+
+		if err := util.UpdateWorkItem(&renamed); err != nil {
+			return alert.Alert(fmt.Sprintf("Failed to save item: %v", err), alert.AlertTypeError)()
+		}
+		return data.UpdateWorkItemMsg{WorkItem: &renamed}
+	}
+}
+
+func nameTaken(name string, workitem *data.WorkItem, others []*data.WorkItem) bool {
+	for _, other := range others {
+		if other.Id == workitem.Id {
+			continue
+		}
+		if other.BranchName == name || other.WindowName == name {
+			return true
+		}
+	}
+	return util.WindowExists(name, util.TmuxSessionName())
+}
+
+func worktreeTaken(name string) bool {
+	worktreePath, err := util.DefaultWorktreePath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(worktreePath)
+	return err == nil
+}
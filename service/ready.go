@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/jquag/ai-mux/util"
+)
+
+// claudeReadyTimeout is how long Claude gets to show its prompt or start working
+const claudeReadyTimeout = 30 * time.Second
+
+var (
+	// trustPrompt is Claude asking whether to trust the files of a folder it hasn't seen
+	trustPrompt = regexp.MustCompile(`(?i)do you trust the files in this folder|yes, proceed`)
+	// claudeReady is Claude's input box or the status line of a running turn
+	claudeReady = regexp.MustCompile(`(?i)\? for shortcuts|esc to interrupt|accept edits on|plan mode on|bypass permissions on`)
+)
+
+// waitForClaude watches the pane Claude was started in with command until it is ready,
+// accepting the workspace trust prompt if Claude shows it
+func waitForClaude(paneId string, command string) error {
+	deadline := time.Now().Add(claudeReadyTimeout)
+	patterns := []*regexp.Regexp{claudeReady, trustPrompt}
+	for {
+		match, err := util.WaitForPane(paneId, patterns, command, time.Until(deadline))
+		if err != nil {
+			return fmt.Errorf("claude did not become ready: %w", err)
+		}
+		if match == 0 {
+			return nil
+		}
+
+		if err := util.SendKeysToTmuxPane(paneId, "Enter"); err != nil {
+			return fmt.Errorf("failed to accept the trust prompt: %w", err)
+		}
+		// The prompt can linger for a moment after it is answered, don't answer it twice
+		patterns = patterns[:1]
+	}
+}
@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jquag/ai-mux/component/alert"
 	data "github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/settings"
 	"github.com/jquag/ai-mux/util"
 )
 
@@ -20,10 +20,18 @@ type StartSessionMsg struct {
 	Error                 error
 }
 
-func StartSession(workitem *data.WorkItem, mode string) tea.Cmd {
+// StartSession starts the work item's session. A new branch is created from baseBranch, or
+// from HEAD when it is empty.
+func StartSession(workitem *data.WorkItem, mode string, baseBranch string) tea.Cmd {
 	return func() tea.Msg {
-		safeName := util.ToSafeName(workitem.ShortName)
-		worktreePath, err := util.CreateWorktree(safeName)
+		// The prerequisite's branch may have been deleted before it was seen to be merged
+		warning := ""
+		if baseBranch != "" && !util.BranchExists(baseBranch) {
+			warning = fmt.Sprintf("Branch %s is gone and was not seen merged, %s was started from HEAD instead.", baseBranch, workitem.ShortName)
+			baseBranch = ""
+		}
+
+		worktreePath, err := ensureWorktree(workitem, baseBranch)
 		if err != nil {
 			return alert.Alert(fmt.Sprintf("Failed to create worktree: %v", err), alert.AlertTypeError)()
 		}
@@ -37,20 +45,20 @@ func StartSession(workitem *data.WorkItem, mode string) tea.Cmd {
 			return alert.Alert(fmt.Sprintf("Failed to start Claude: %v", err), alert.AlertTypeError)()
 		}
 
+		if warning != "" {
+			return alert.Alert(warning, alert.AlertTypeWarning)()
+		}
 		return nil
 	}
 }
 
 func ResumeSession(workitem *data.WorkItem) tea.Cmd {
 	return func() tea.Msg {
-		// Get worktree path
-		cwd, err := os.Getwd()
+		// Imported items may only be bound to a branch, so make sure the worktree exists
+		worktreePath, err := ensureWorktree(workitem, "")
 		if err != nil {
-			return alert.Alert(fmt.Sprintf("Failed to get current directory: %v", err), alert.AlertTypeError)()
+			return alert.Alert(fmt.Sprintf("Failed to create worktree: %v", err), alert.AlertTypeError)()
 		}
-		mainFolderName := filepath.Base(cwd)
-		safeName := util.ToSafeName(workitem.ShortName)
-		worktreePath := filepath.Join("..", fmt.Sprintf("%s-worktrees", mainFolderName), safeName)
 		
 		// Ensure tmux window and panes are set up (will reuse existing if present)
 		if err := setupTmuxWindow(workitem, worktreePath); err != nil {
@@ -68,34 +76,23 @@ func ResumeSession(workitem *data.WorkItem) tea.Cmd {
 
 func CloseSession(workitem *data.WorkItem) tea.Cmd {
 	return func() tea.Msg {
-		// Calculate session name once
-		sessionName := ""
-		if !util.InTmuxSession() {
-			sessionName = "ai-mux"
-		}
+		sessionName := util.TmuxSessionName()
 
 		// Check if work item has been started
 		isStarted := workitem.Status != "created" && workitem.Status != ""
 
 		if isStarted {
-			// Get worktree path
-			cwd, err := os.Getwd()
-			if err != nil {
-				return alert.Alert("Failed to get current directory: "+err.Error(), alert.AlertTypeError)()
-			}
-			mainFolderName := filepath.Base(cwd)
-			safeName := util.ToSafeName(workitem.ShortName)
-			worktreePath := filepath.Join("..", fmt.Sprintf("%s-worktrees", mainFolderName), safeName)
+			worktreePath := workitem.WorktreePath
 
 			// Check if worktree is clean and tell claude to commit if needed
 			if clean, err := util.IsWorktreeClean(worktreePath); err == nil && !clean {
 				// Find Claude pane by custom variable
-				claudePaneId, err := util.FindPaneByVariable(safeName, sessionName, "role", "claude-ai")
+				claudePaneId, err := util.FindPaneByVariable(workitem.WindowName, sessionName, "role", "claude-ai")
 				if err != nil {
 					return alert.Alert("Could not find Claude pane: "+err.Error(), alert.AlertTypeError)()
 				}
 				
-				err = util.RunCommandInTmuxPane(claudePaneId, "commit the changes")
+				err = util.SubmitTextToTmuxPane(claudePaneId, "commit the changes")
 				if err != nil {
 					return alert.Alert("Failed to commit changes: "+err.Error(), alert.AlertTypeError)()
 				}
@@ -104,11 +101,19 @@ func CloseSession(workitem *data.WorkItem) tea.Cmd {
 				return nil // Need to wait for claude to finish commiting
 			}
 
-			// Remove tmux window
-			util.KillTmuxWindow(safeName, sessionName)
+			// Remove tmux window, the user may have closed it already
+			if util.WindowExists(workitem.WindowName, sessionName) {
+				if err := util.KillTmuxWindow(workitem.WindowName, sessionName); err != nil {
+					return alert.Alert("Failed to kill tmux window: "+err.Error(), alert.AlertTypeError)()
+				}
+			}
 
-			// Remove git worktree
-			util.RemoveWorktree(worktreePath)
+			// Remove git worktree, unless it was the user's before the item was imported
+			if _, err := os.Stat(worktreePath); err == nil && !workitem.AdoptedWorktree {
+				if err := util.RemoveWorktree(worktreePath); err != nil {
+					return alert.Alert(err.Error(), alert.AlertTypeError)()
+				}
+			}
 		}
 
 		// Always remove work item data at the end
@@ -123,17 +128,71 @@ func CloseSession(workitem *data.WorkItem) tea.Cmd {
 	}
 }
 
+// Approve accepts the prompt Claude is waiting on by choosing its default answer
+func Approve(workitem *data.WorkItem) tea.Cmd {
+	return func() tea.Msg {
+		claudePaneId, err := util.FindPaneByVariable(workitem.WindowName, util.TmuxSessionName(), "role", "claude-ai")
+		if err != nil {
+			return alert.Alert("Could not find Claude pane: "+err.Error(), alert.AlertTypeError)()
+		}
+		if err := util.SendKeysToTmuxPane(claudePaneId, "Enter"); err != nil {
+			return alert.Alert(err.Error(), alert.AlertTypeError)()
+		}
+		return nil
+	}
+}
+
+// CloseSummary describes what closing a work item will destroy
+func CloseSummary(workitem *data.WorkItem) []string {
+	summary := []string{}
+
+	if workitem.IsStarted() {
+		if workitem.IsWorking() {
+			summary = append(summary, "Claude is still working and will be stopped")
+		}
+
+		summary = append(summary, fmt.Sprintf("Tmux window %s will be killed", workitem.WindowName))
+		if workitem.AdoptedWorktree {
+			summary = append(summary, fmt.Sprintf("Worktree %s was imported and is kept, as is branch %s", workitem.WorktreePath, workitem.BranchName))
+		} else {
+			summary = append(summary, fmt.Sprintf("Worktree %s will be removed (branch %s is kept)", workitem.WorktreePath, workitem.BranchName))
+		}
+
+		if files, err := util.UncommittedFiles(workitem.WorktreePath); err == nil && len(files) > 0 {
+			summary = append(summary, fmt.Sprintf("%d uncommitted file(s), Claude will be asked to commit them first", len(files)))
+		}
+
+		if base, err := util.CurrentBranch(); err == nil {
+			if commits, err := util.UnpushedCommits(workitem.WorktreePath, base); err == nil && len(commits) > 0 {
+				summary = append(summary, fmt.Sprintf("%d unpushed commit(s) on %s", len(commits), workitem.BranchName))
+			}
+		}
+	}
+
+	summary = append(summary, "The work item and its status history will be deleted")
+	return summary
+}
+
 func setupTmuxWindow(workitem *data.WorkItem, worktreePath string) error {
-	sessionName := ""
-	if !util.InTmuxSession() {
-		sessionName = "ai-mux"
-		// Ensure ai-mux session exists
-		if _, err := util.EnsureTmuxSession("ai-mux"); err != nil {
-			return fmt.Errorf("failed to create tmux session 'ai-mux': %w", err)
+	sessionName := util.TmuxSessionName()
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	// Ensure the repository's session exists, it does already when ai-mux was launched into it
+	created, err := util.EnsureTmuxSession(sessionName)
+	if err != nil {
+		return fmt.Errorf("failed to create tmux session '%s': %w", sessionName, err)
+	}
+	if created {
+		if err := util.SetSessionDir(sessionName, dir); err != nil {
+			return err
 		}
+	} else if err := util.CheckSessionDir(sessionName, dir); err != nil {
+		return err
 	}
 	
-	safeName := util.ToSafeName(workitem.ShortName)
+	safeName := workitem.WindowName
 	
 	// Check if window already exists
 	windowExists := util.WindowExists(safeName, sessionName)
@@ -144,13 +203,15 @@ func setupTmuxWindow(workitem *data.WorkItem, worktreePath string) error {
 			return fmt.Errorf("failed to create tmux window: %w", err)
 		}
 		
-		// Start editor in the top pane (original pane)
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vim" // Default fallback
-		}
-		if err := util.RunCommandInTmuxWindow(safeName, sessionName, editor); err != nil {
-			return fmt.Errorf("failed to start editor: %w", err)
+		// Start editor in the top pane (original pane), unless Claude gets the window to itself
+		if workitem.Layout != data.LayoutClaude {
+			editor := os.Getenv("EDITOR")
+			if editor == "" {
+				editor = "vim" // Default fallback
+			}
+			if err := util.SubmitTextToTmuxPane(util.WindowTarget(safeName, sessionName), editor); err != nil {
+				return fmt.Errorf("failed to start editor: %w", err)
+			}
 		}
 	}
 	
@@ -158,71 +219,70 @@ func setupTmuxWindow(workitem *data.WorkItem, worktreePath string) error {
 	_, claudePaneErr := util.FindPaneByVariable(safeName, sessionName, "role", "claude-ai")
 	
 	if claudePaneErr != nil {
-		// Claude pane doesn't exist, create the split
-		if err := util.SplitTmuxWindow(safeName, sessionName, worktreePath); err != nil {
-			return fmt.Errorf("failed to split tmux window: %w", err)
+		// Claude pane doesn't exist, create the split for it
+		paneSuffix := ".{bottom}"
+		switch workitem.Layout {
+		case data.LayoutClaude:
+			paneSuffix = ".{top}"
+		case data.LayoutSideBySide:
+			paneSuffix = ".{right}"
 		}
-		
-		// Set custom variables for the bottom pane
-		target := safeName
-		if sessionName != "" {
-			target = sessionName + ":" + safeName
+		if workitem.Layout != data.LayoutClaude {
+			if err := util.SplitTmuxWindow(safeName, sessionName, worktreePath, workitem.Layout == data.LayoutSideBySide); err != nil {
+				return fmt.Errorf("failed to split tmux window: %w", err)
+			}
 		}
-		bottomPane := target + ".{bottom}"
 		
-		util.SetPaneVariable(bottomPane, "role", "claude-ai")
-		util.SetPaneVariable(bottomPane, "workitem-id", workitem.Id)
+		// Set custom variables for the Claude pane
+		claudePane := util.WindowTarget(safeName, sessionName) + paneSuffix
+		if err := util.SetPaneVariable(claudePane, "role", "claude-ai"); err != nil {
+			return err
+		}
+		if err := util.SetPaneVariable(claudePane, "workitem-id", workitem.Id); err != nil {
+			return err
+		}
 	}
 	
 	return nil
 }
 
 func startClaudeInWindow(workitem *data.WorkItem, mode string) error {
-	// Get the current directory name (main folder)
-	cwd, err := os.Getwd()
+	// Build the absolute path to the ai-mux directory in the main tree so it
+	// resolves from any worktree, including imported ones outside the worktrees folder
+	aiMuxDirPath, err := filepath.Abs(util.AiMuxDir)
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return fmt.Errorf("failed to resolve %s: %w", util.AiMuxDir, err)
 	}
-	mainFolderName := filepath.Base(cwd)
-
-	// Build the path to the ai-mux directory in the main tree
-	// From worktree at ../mainFolder-worktrees/branchName to ../mainFolder/.ai-mux
-	aiMuxDirPath := filepath.Join("..", "..", mainFolderName, ".ai-mux")
-	settingsPath := filepath.Join(aiMuxDirPath, "claude-settings.json")
-
-	// Build the claude command based on mode
-	var claudeCmd string
-	if mode == "resume" {
-		// For resume, use --resume instead of --session-id and don't pass the prompt
-		claudeCmd = fmt.Sprintf("AI_MUX_DIR=%s claude --resume %s --settings %s",
-			aiMuxDirPath, workitem.Id, settingsPath)
-	} else {
-		// For start modes (default, plan, acceptEdits), use --session-id and pass the prompt
-		claudeCmd = fmt.Sprintf("AI_MUX_DIR=%s claude --session-id %s --settings %s --permission-mode %s %s",
-			aiMuxDirPath, workitem.Id, settingsPath, mode, util.ShellQuote(workitem.Description))
+	settingsPath, err := settings.Generate(aiMuxDirPath, workitem.Id)
+	if err != nil {
+		return fmt.Errorf("failed to generate Claude settings: %w", err)
 	}
 
-	// Determine session name
-	sessionName := ""
-	if !util.InTmuxSession() {
-		sessionName = "ai-mux"
-	}
-	
+	claudeCmd := claudeCommand(workitem, mode, aiMuxDirPath, settingsPath)
+
 	// Find Claude pane by custom variable
-	safeName := util.ToSafeName(workitem.ShortName)
-	claudePaneId, err := util.FindPaneByVariable(safeName, sessionName, "role", "claude-ai")
+	claudePaneId, err := util.FindPaneByVariable(workitem.WindowName, util.TmuxSessionName(), "role", "claude-ai")
 	if err != nil {
 		return fmt.Errorf("could not find Claude pane: %w", err)
 	}
 
 	// Run the command in the Claude pane
-	err = util.RunCommandInTmuxPane(claudePaneId, claudeCmd)
+	if err := util.SubmitTextToTmuxPane(claudePaneId, claudeCmd); err != nil {
+		return err
+	}
+
+	// Claude may ask to trust the worktree before it takes the prompt, it has no hook for that
+	return waitForClaude(claudePaneId, claudeCmd)
+}
+
+// ensureWorktree returns the worktree of the work item, creating it when it does not exist yet
+func ensureWorktree(workitem *data.WorkItem, baseBranch string) (string, error) {
+	if _, err := os.Stat(workitem.WorktreePath); err == nil {
+		return workitem.WorktreePath, nil
+	}
 
-	// For non-resume modes, wait for claude to bring up the trust prompt then automatically accept it
-	// This is a hack but I didn't see any other way to do it and claude does not provide a notification when this prompt appears
-	if mode != "resume" {
-		time.Sleep(2 * time.Second)
-		util.RunCommandInTmuxPane(claudePaneId, "Enter")
+	if err := util.CreateWorktree(workitem.WorktreePath, workitem.BranchName, baseBranch); err != nil {
+		return "", err
 	}
-	return err
+	return workitem.WorktreePath, nil
 }
@@ -0,0 +1,282 @@
+package settings
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jquag/ai-mux/util"
+)
+
+// embedded holds the hooks ai-mux needs and the default permissions
+//
+//go:embed claudeSettings.json
+var embedded []byte
+
+const (
+	// FileName is the base settings in the ai-mux directory and the settings generated for
+	// each work item in its folder
+	FileName = "claude-settings.json"
+	// OverridesFileName in a work item's folder is merged over the base for that item only
+	OverridesFileName = "claude-settings.override.json"
+
+	// eventCommandKey stands for any ai-mux hook command when comparing hooks
+	eventCommandKey = "ai-mux --event"
+)
+
+// EnsureBase writes the base settings to dir when they don't exist yet. Existing base
+// settings are kept, with hooks added in newer versions of ai-mux merged in.
+func EnsureBase(dir string) error {
+	path := filepath.Join(dir, FileName)
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if err := os.WriteFile(path, embedded, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var base, defaults map[string]any
+	if err := json.Unmarshal(content, &base); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := json.Unmarshal(embedded, &defaults); err != nil {
+		return fmt.Errorf("failed to parse embedded settings: %w", err)
+	}
+	if !mergeHooks(base, defaults) {
+		return nil
+	}
+	return writeJSON(path, base)
+}
+
+// Generate writes the settings of a work item to its folder in dir, the base with the
+// item's overrides merged over it and the hooks running this executable, and returns
+// their path
+func Generate(dir string, itemId string) (string, error) {
+	settings, err := readJSON(filepath.Join(dir, FileName))
+	if os.IsNotExist(err) {
+		err = json.Unmarshal(embedded, &settings)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	itemDir := filepath.Join(dir, itemId)
+	overrides, err := readJSON(filepath.Join(itemDir, OverridesFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if overrides != nil {
+		settings = merge(settings, overrides).(map[string]any)
+	}
+	command, err := EventCommand()
+	if err != nil {
+		return "", err
+	}
+	rewriteEventCommands(settings, command)
+
+	if err := os.MkdirAll(itemDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create item directory: %w", err)
+	}
+	path := filepath.Join(itemDir, FileName)
+	if err := writeJSON(path, settings); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// merge returns src merged over dst. Objects are merged key by key, lists get the entries
+// of src they don't have yet and anything else is replaced.
+func merge(dst, src any) any {
+	switch src := src.(type) {
+	case map[string]any:
+		d, ok := dst.(map[string]any)
+		if !ok {
+			return src
+		}
+		for key, value := range src {
+			d[key] = merge(d[key], value)
+		}
+		return d
+	case []any:
+		d, ok := dst.([]any)
+		if !ok {
+			return src
+		}
+		for _, value := range src {
+			if !contains(d, value) {
+				d = append(d, value)
+			}
+		}
+		return d
+	default:
+		return src
+	}
+}
+
+func contains(list []any, value any) bool {
+	for _, v := range list {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeHooks adds the hook groups of defaults to base for every event where base doesn't
+// run their commands yet, leaving the user's own hooks and edits alone. It reports whether
+// base changed.
+func mergeHooks(base, defaults map[string]any) bool {
+	defaultHooks, _ := defaults["hooks"].(map[string]any)
+	hooks, ok := base["hooks"].(map[string]any)
+	if !ok {
+		hooks = map[string]any{}
+	}
+
+	changed := false
+	for event, value := range defaultHooks {
+		groups, _ := hooks[event].([]any)
+		existing := commands(groups)
+		for _, group := range value.([]any) {
+			for command := range commands([]any{group}) {
+				if !existing[command] {
+					groups = append(groups, group)
+					changed = true
+					break
+				}
+			}
+		}
+		hooks[event] = groups
+	}
+	if changed {
+		base["hooks"] = hooks
+	}
+	return changed
+}
+
+// commands returns the commands run by the hook groups of an event
+func commands(groups []any) map[string]bool {
+	found := map[string]bool{}
+	for _, group := range groups {
+		g, _ := group.(map[string]any)
+		hooks, _ := g["hooks"].([]any)
+		for _, hook := range hooks {
+			h, _ := hook.(map[string]any)
+			if command, ok := h["command"].(string); ok {
+				found[command] = true
+				// Older versions named the binary aimux, their hooks are the same hooks
+				if isEventCommand(command) {
+					found[eventCommandKey] = true
+				}
+			}
+		}
+	}
+	return found
+}
+
+func readJSON(path string) (map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var value map[string]any
+	if err := json.Unmarshal(content, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if value == nil {
+		value = map[string]any{}
+	}
+	return value, nil
+}
+
+func writeJSON(path string, value map[string]any) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// EventCommand is the hook command reporting Claude events to this ai-mux binary, by its
+// absolute path so hooks work whatever it is named and whether or not it is on PATH
+func EventCommand() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the ai-mux executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	return util.ShellQuote(executable) + " --event", nil
+}
+
+// isEventCommand reports whether a hook command is ai-mux's, under the name used in the
+// embedded settings or the name older versions used
+func isEventCommand(command string) bool {
+	fields := strings.Fields(command)
+	if len(fields) != 2 || fields[1] != "--event" {
+		return false
+	}
+	name := filepath.Base(strings.Trim(fields[0], "'\""))
+	return name == "ai-mux" || name == "aimux"
+}
+
+// rewriteEventCommands points every ai-mux hook in the settings at command
+func rewriteEventCommands(settings map[string]any, command string) {
+	hooks, _ := settings["hooks"].(map[string]any)
+	for _, groups := range hooks {
+		groups, _ := groups.([]any)
+		for _, group := range groups {
+			g, _ := group.(map[string]any)
+			entries, _ := g["hooks"].([]any)
+			for _, entry := range entries {
+				e, _ := entry.(map[string]any)
+				if c, ok := e["command"].(string); ok && isEventCommand(c) {
+					e["command"] = command
+				}
+			}
+		}
+	}
+}
+
+// SelfCheck runs ai-mux's hook command the way Claude does with a synthetic event and
+// checks that the event is recorded
+func SelfCheck() error {
+	command, err := EventCommand()
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "ai-mux-self-check-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), "AI_MUX_DIR="+dir)
+	cmd.Stdin = strings.NewReader(`{"session_id":"self-check","hook_event_name":"SelfCheck"}`)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("hook command %s failed: %w - %s", command, err, strings.TrimSpace(string(output)))
+	}
+
+	log, err := os.ReadFile(filepath.Join(dir, "self-check", "state-log.txt"))
+	if err != nil || !strings.Contains(string(log), "SelfCheck") {
+		return fmt.Errorf("hook command %s ran but did not record the event", command)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package settings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// defaults stands in for the embedded settings, one event with a matcher and one without
+const defaults = `{"hooks": {
+	"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "ai-mux --event"}]}],
+	"Stop": [{"hooks": [{"type": "command", "command": "ai-mux --event"}]}]
+}}`
+
+func parse(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var value map[string]any
+	if err := json.Unmarshal([]byte(s), &value); err != nil {
+		t.Fatalf("failed to parse %s: %v", s, err)
+	}
+	return value
+}
+
+func TestMergeHooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		base        string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name: "up to date",
+			base: defaults,
+			want: defaults,
+		},
+		{
+			name: "legacy aimux hooks are not duplicated",
+			base: `{"hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "aimux --event"}]}],
+				"Stop": [{"hooks": [{"type": "command", "command": "aimux --event"}]}]
+			}}`,
+			want: `{"hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "aimux --event"}]}],
+				"Stop": [{"hooks": [{"type": "command", "command": "aimux --event"}]}]
+			}}`,
+		},
+		{
+			name: "hooks run by path are not duplicated",
+			base: `{"hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "'/usr/local/bin/ai-mux' --event"}]}],
+				"Stop": [{"hooks": [{"type": "command", "command": "/opt/aimux --event"}]}]
+			}}`,
+			want: `{"hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "'/usr/local/bin/ai-mux' --event"}]}],
+				"Stop": [{"hooks": [{"type": "command", "command": "/opt/aimux --event"}]}]
+			}}`,
+		},
+		{
+			name: "missing event is added",
+			base: `{"hooks": {
+				"Stop": [{"hooks": [{"type": "command", "command": "ai-mux --event"}]}]
+			}}`,
+			want:        defaults,
+			wantChanged: true,
+		},
+		{
+			name: "no hooks at all",
+			base: `{"permissions": {"allow": ["Bash(ls:*)"]}}`,
+			want: `{"permissions": {"allow": ["Bash(ls:*)"]}, "hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "ai-mux --event"}]}],
+				"Stop": [{"hooks": [{"type": "command", "command": "ai-mux --event"}]}]
+			}}`,
+			wantChanged: true,
+		},
+		{
+			name: "user hooks are kept ahead of added ones",
+			base: `{"hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "ai-mux --event"}]}],
+				"Stop": [
+					{"hooks": [{"type": "command", "command": "say done"}]},
+					{"hooks": [{"type": "command", "command": "notify-send done"}]}
+				],
+				"PreToolUse": [{"matcher": "Bash", "hooks": [{"type": "command", "command": "./check.sh"}]}]
+			}}`,
+			want: `{"hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "ai-mux --event"}]}],
+				"Stop": [
+					{"hooks": [{"type": "command", "command": "say done"}]},
+					{"hooks": [{"type": "command", "command": "notify-send done"}]},
+					{"hooks": [{"type": "command", "command": "ai-mux --event"}]}
+				],
+				"PreToolUse": [{"matcher": "Bash", "hooks": [{"type": "command", "command": "./check.sh"}]}]
+			}}`,
+			wantChanged: true,
+		},
+		{
+			name: "ai-mux hook sharing a group with a user hook",
+			base: `{"hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "ai-mux --event"}]}],
+				"Stop": [{"hooks": [
+					{"type": "command", "command": "say done"},
+					{"type": "command", "command": "aimux --event"}
+				]}]
+			}}`,
+			want: `{"hooks": {
+				"Notification": [{"matcher": "", "hooks": [{"type": "command", "command": "ai-mux --event"}]}],
+				"Stop": [{"hooks": [
+					{"type": "command", "command": "say done"},
+					{"type": "command", "command": "aimux --event"}
+				]}]
+			}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := parse(t, tt.base)
+			changed := mergeHooks(base, parse(t, defaults))
+			if changed != tt.wantChanged {
+				t.Errorf("mergeHooks() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if want := parse(t, tt.want); !reflect.DeepEqual(base, want) {
+				got, _ := json.Marshal(base)
+				wantJSON, _ := json.Marshal(want)
+				t.Errorf("mergeHooks()\n got: %s\nwant: %s", got, wantJSON)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  string
+		src  string
+		want string
+	}{
+		{
+			name: "lists get new entries only",
+			dst:  `{"permissions": {"allow": ["Bash(git add:*)", "Bash(git commit:*)"]}}`,
+			src:  `{"permissions": {"allow": ["Bash(git commit:*)", "Bash(go test:*)"]}}`,
+			want: `{"permissions": {"allow": ["Bash(git add:*)", "Bash(git commit:*)", "Bash(go test:*)"]}}`,
+		},
+		{
+			name: "equal objects in lists are not duplicated",
+			dst:  `{"hooks": {"Stop": [{"hooks": [{"type": "command", "command": "ai-mux --event"}]}]}}`,
+			src:  `{"hooks": {"Stop": [{"hooks": [{"command": "ai-mux --event", "type": "command"}]}, {"hooks": [{"type": "command", "command": "say done"}]}]}}`,
+			want: `{"hooks": {"Stop": [{"hooks": [{"type": "command", "command": "ai-mux --event"}]}, {"hooks": [{"type": "command", "command": "say done"}]}]}}`,
+		},
+		{
+			name: "objects merge key by key",
+			dst:  `{"permissions": {"allow": ["Bash(ls:*)"], "deny": []}, "model": "sonnet"}`,
+			src:  `{"permissions": {"deny": ["Bash(rm:*)"]}, "env": {"DEBUG": "1"}}`,
+			want: `{"permissions": {"allow": ["Bash(ls:*)"], "deny": ["Bash(rm:*)"]}, "model": "sonnet", "env": {"DEBUG": "1"}}`,
+		},
+		{
+			name: "scalars are replaced",
+			dst:  `{"model": "sonnet", "includeCoAuthoredBy": true}`,
+			src:  `{"model": "opus", "includeCoAuthoredBy": false}`,
+			want: `{"model": "opus", "includeCoAuthoredBy": false}`,
+		},
+		{
+			name: "mismatched types are replaced",
+			dst:  `{"permissions": ["Bash(ls:*)"], "env": "none"}`,
+			src:  `{"permissions": {"allow": ["Bash(ls:*)"]}, "env": ["DEBUG"]}`,
+			want: `{"permissions": {"allow": ["Bash(ls:*)"]}, "env": ["DEBUG"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := merge(parse(t, tt.dst), parse(t, tt.src))
+			if want := parse(t, tt.want); !reflect.DeepEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(want)
+				t.Errorf("merge(%s, %s)\n got: %s\nwant: %s", tt.dst, tt.src, gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestEnsureBase(t *testing.T) {
+	// Legacy names and the user's own formatting and keys, complete as far as hooks go
+	legacy := strings.ReplaceAll(string(embedded), `"ai-mux --event"`, `"aimux --event"`)
+	legacy = strings.Replace(legacy, `"permissions": {`, `"model": "opus",   "permissions": {`, 1)
+
+	tests := []struct {
+		name        string
+		base        string // empty when there is no base yet
+		wantWritten bool
+	}{
+		{
+			name:        "no base writes the embedded settings",
+			wantWritten: true,
+		},
+		{
+			name: "embedded settings are left alone",
+			base: string(embedded),
+		},
+		{
+			name: "complete legacy settings are left alone",
+			base: legacy,
+		},
+		{
+			name:        "missing hooks are written",
+			base:        `{"hooks": {"Stop": [{"hooks": [{"type": "command", "command": "aimux --event"}]}]}}`,
+			wantWritten: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, FileName)
+			if tt.base != "" {
+				if err := os.WriteFile(path, []byte(tt.base), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := EnsureBase(dir); err != nil {
+				t.Fatalf("EnsureBase() failed: %v", err)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if written := string(content) != tt.base; written != tt.wantWritten {
+				t.Errorf("EnsureBase() wrote the file = %v, want %v, it holds:\n%s", written, tt.wantWritten, content)
+			}
+
+			// Whatever was written, running again must not change it
+			if err := EnsureBase(dir); err != nil {
+				t.Fatalf("EnsureBase() failed the second time: %v", err)
+			}
+			again, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(content) {
+				t.Errorf("EnsureBase() changed its own result:\n%s\nto:\n%s", content, again)
+			}
+		})
+	}
+}
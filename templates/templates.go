@@ -0,0 +1,149 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/util"
+)
+
+// Dir holds one json file per template
+var Dir = filepath.Join(util.AiMuxDir, "templates")
+
+// Template prefills a new work item. ShortName and Description may contain placeholders
+// like {{ticket}} that are asked for when the template is used.
+type Template struct {
+	Name        string   `json:"name"`
+	ShortName   string   `json:"shortName"`
+	Description string   `json:"description"`
+	StartMode   string   `json:"startMode,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Layout      string   `json:"layout,omitempty"`
+	AutoStart   bool     `json:"autoStart,omitempty"`
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// List reads the templates sorted by name. A template without a name is named after its file.
+func List() ([]Template, error) {
+	files, err := filepath.Glob(filepath.Join(Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := []Template{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", file, err)
+		}
+		var t Template
+		if err := json.Unmarshal(content, &t); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", file, err)
+		}
+		if t.Name == "" {
+			t.Name = strings.TrimSuffix(filepath.Base(file), ".json")
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return strings.ToLower(templates[i].Name) < strings.ToLower(templates[j].Name)
+	})
+	return templates, nil
+}
+
+// Save writes the template to a file named after it. It fails when that file exists, different
+// names can share a file.
+func Save(t Template) error {
+	if err := os.MkdirAll(Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	content, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+	path := filepath.Join(Dir, FileName(t.Name))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create template %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(content, '\n')); err != nil {
+		return fmt.Errorf("failed to write template %s: %w", path, err)
+	}
+	return nil
+}
+
+// CheckName returns an error when a template of the given name can't be saved, because its
+// file name would have no letter or digit or a template is saved to that file already
+func CheckName(name string) error {
+	fileName := FileName(name)
+	if strings.Trim(strings.TrimSuffix(fileName, ".json"), "-_") == "" {
+		return fmt.Errorf("template name needs a letter a-z or a digit")
+	}
+	if _, err := os.Stat(filepath.Join(Dir, fileName)); err == nil {
+		return fmt.Errorf("a template is saved to %s already, edit it there or pick another name", fileName)
+	}
+	return nil
+}
+
+// FileName is the file a template of the given name is saved to
+func FileName(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(strings.TrimSpace(name)))
+	return slug + ".json"
+}
+
+// FromItem makes a template of the work item
+func FromItem(item *data.WorkItem, name string) Template {
+	return Template{
+		Name:        name,
+		ShortName:   item.ShortName,
+		Description: item.Description,
+		StartMode:   item.StartMode,
+		Tags:        item.Tags,
+		Layout:      item.Layout,
+		AutoStart:   item.AutoStart,
+	}
+}
+
+// Placeholders lists the placeholder names in the template in the order they first appear
+func (t Template) Placeholders() []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(t.ShortName+"\n"+t.Description, -1) {
+		if name := match[1]; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Item returns a new work item from the template with its placeholders replaced by values
+func (t Template) Item(values map[string]string, order int) *data.WorkItem {
+	fill := func(s string) string {
+		return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+			return values[placeholderPattern.FindStringSubmatch(match)[1]]
+		})
+	}
+	return &data.WorkItem{
+		ShortName:   strings.TrimSpace(fill(t.ShortName)),
+		Description: fill(t.Description),
+		StartMode:   t.StartMode,
+		Tags:        t.Tags,
+		Layout:      t.Layout,
+		AutoStart:   t.AutoStart,
+		Order:       order,
+	}
+}
@@ -0,0 +1,55 @@
+package theme
+
+import (
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Huh returns a form theme matching the active colors
+func Huh() *huh.Theme {
+	t := huh.ThemeBase()
+	if Colors.Monochrome {
+		return t
+	}
+
+	t.Focused.Base = t.Focused.Base.BorderForeground(Colors.Border)
+	t.Focused.Card = t.Focused.Base
+	t.Focused.Title = t.Focused.Title.Foreground(Colors.Title)
+	t.Focused.NoteTitle = t.Focused.NoteTitle.Foreground(Colors.Title)
+	t.Focused.Directory = t.Focused.Directory.Foreground(Colors.Title)
+	t.Focused.Description = t.Focused.Description.Foreground(Colors.Muted)
+	t.Focused.ErrorIndicator = t.Focused.ErrorIndicator.Foreground(Colors.Error)
+	t.Focused.ErrorMessage = t.Focused.ErrorMessage.Foreground(Colors.Error)
+	t.Focused.SelectSelector = t.Focused.SelectSelector.Foreground(Colors.Primary)
+	t.Focused.NextIndicator = t.Focused.NextIndicator.Foreground(Colors.Primary)
+	t.Focused.PrevIndicator = t.Focused.PrevIndicator.Foreground(Colors.Primary)
+	t.Focused.Option = t.Focused.Option.Foreground(Colors.Text)
+	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(Colors.Primary)
+	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(Colors.Success)
+	t.Focused.SelectedPrefix = t.Focused.SelectedPrefix.Foreground(Colors.Success)
+	t.Focused.UnselectedPrefix = t.Focused.UnselectedPrefix.Foreground(Colors.Text)
+	t.Focused.UnselectedOption = t.Focused.UnselectedOption.Foreground(Colors.Text)
+	t.Focused.FocusedButton = t.Focused.FocusedButton.Foreground(Colors.Text).Background(Colors.BgDark).Bold(true)
+	t.Focused.BlurredButton = t.Focused.BlurredButton.Foreground(Colors.Muted).Background(lipgloss.NoColor{})
+
+	t.Focused.TextInput.Cursor = t.Focused.TextInput.Cursor.Foreground(Colors.Primary)
+	t.Focused.TextInput.Placeholder = t.Focused.TextInput.Placeholder.Foreground(Colors.Muted)
+	t.Focused.TextInput.Prompt = t.Focused.TextInput.Prompt.Foreground(Colors.Primary)
+	t.Focused.TextInput.Text = t.Focused.TextInput.Text.Foreground(Colors.Text)
+
+	t.Blurred = t.Focused
+	t.Blurred.Base = t.Blurred.Base.BorderStyle(lipgloss.HiddenBorder())
+	t.Blurred.Card = t.Blurred.Base
+
+	t.Help.Ellipsis = t.Help.Ellipsis.Foreground(Colors.Muted)
+	t.Help.ShortKey = t.Help.ShortKey.Foreground(Colors.Muted)
+	t.Help.ShortDesc = t.Help.ShortDesc.Foreground(Colors.Muted)
+	t.Help.ShortSeparator = t.Help.ShortSeparator.Foreground(Colors.Muted)
+	t.Help.FullKey = t.Help.FullKey.Foreground(Colors.Muted)
+	t.Help.FullDesc = t.Help.FullDesc.Foreground(Colors.Muted)
+	t.Help.FullSeparator = t.Help.FullSeparator.Foreground(Colors.Muted)
+
+	t.Group.Title = t.Focused.Title
+	t.Group.Description = t.Focused.Description
+	return t
+}
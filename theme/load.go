@@ -0,0 +1,119 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// themeFile is a user theme, a preset with some colors replaced. Each color is either
+// a single color or an object with "light" and "dark" variants.
+type themeFile struct {
+	Base   string                `json:"base"`
+	Colors map[string]colorValue `json:"colors"`
+}
+
+type colorValue struct {
+	color lipgloss.TerminalColor
+}
+
+func (c *colorValue) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		c.color = lipgloss.Color(single)
+		return nil
+	}
+
+	var pair struct {
+		Light string `json:"light"`
+		Dark  string `json:"dark"`
+	}
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return fmt.Errorf("a color must be a string or an object with light and dark")
+	}
+	c.color = lipgloss.AdaptiveColor{Light: pair.Light, Dark: pair.Dark}
+	return nil
+}
+
+// Load makes the named theme active. Presets are checked first, then <name>.json in
+// each of dirs. Setting NO_COLOR forces the monochrome theme.
+func Load(name string, dirs ...string) error {
+	if os.Getenv("NO_COLOR") != "" {
+		Colors = monochrome
+		return nil
+	}
+	if name == "" {
+		name = DefaultTheme
+	}
+
+	if preset, ok := Presets[name]; ok {
+		Colors = preset
+		return nil
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name+".json")
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read theme %s: %w", path, err)
+		}
+
+		colors, err := parse(content)
+		if err != nil {
+			return fmt.Errorf("failed to parse theme %s: %w", path, err)
+		}
+		Colors = colors
+		return nil
+	}
+
+	names := []string{}
+	for preset := range Presets {
+		names = append(names, preset)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown theme %q, use one of %s or add %s.json to %s",
+		name, strings.Join(names, ", "), name, strings.Join(dirs, " or "))
+}
+
+func parse(content []byte) (ThemeColors, error) {
+	var file themeFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return ThemeColors{}, err
+	}
+
+	if file.Base == "" {
+		file.Base = DefaultTheme
+	}
+	colors, ok := Presets[file.Base]
+	if !ok {
+		return ThemeColors{}, fmt.Errorf("unknown base theme %q", file.Base)
+	}
+
+	fields := map[string]*lipgloss.TerminalColor{
+		"border":    &colors.Border,
+		"primary":   &colors.Primary,
+		"title":     &colors.Title,
+		"muted":     &colors.Muted,
+		"text":      &colors.Text,
+		"success":   &colors.Success,
+		"error":     &colors.Error,
+		"info":      &colors.Info,
+		"selection": &colors.BgDark,
+	}
+	for name, value := range file.Colors {
+		field, ok := fields[name]
+		if !ok {
+			return ThemeColors{}, fmt.Errorf("unknown color %q", name)
+		}
+		*field = value.color
+	}
+	return colors, nil
+}
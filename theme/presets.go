@@ -0,0 +1,101 @@
+package theme
+
+import "github.com/charmbracelet/lipgloss"
+
+const DefaultTheme = "adaptive"
+
+var mocha = ThemeColors{
+	Border:  lipgloss.Color("#89b4fa"),
+	Primary: lipgloss.Color("#f9b387"),
+	Title:   lipgloss.Color("#cba6f7"),
+	Muted:   lipgloss.Color("#9298b1"),
+	Text:    lipgloss.Color("#c6cfec"),
+	Success: lipgloss.Color("#a7e2a1"),
+	Error:   lipgloss.Color("#eba0ac"),
+	Info:    lipgloss.Color("#81d1e0"),
+	BgDark:  lipgloss.Color("#243b40"),
+}
+
+var latte = ThemeColors{
+	Border:  lipgloss.Color("#1e66f5"),
+	Primary: lipgloss.Color("#fe640b"),
+	Title:   lipgloss.Color("#8839ef"),
+	Muted:   lipgloss.Color("#7c7f93"),
+	Text:    lipgloss.Color("#4c4f69"),
+	Success: lipgloss.Color("#40a02b"),
+	Error:   lipgloss.Color("#d20f39"),
+	Info:    lipgloss.Color("#04a5e5"),
+	BgDark:  lipgloss.Color("#ccd0da"),
+}
+
+var gruvbox = ThemeColors{
+	Border:  lipgloss.Color("#83a598"),
+	Primary: lipgloss.Color("#fe8019"),
+	Title:   lipgloss.Color("#d3869b"),
+	Muted:   lipgloss.Color("#928374"),
+	Text:    lipgloss.Color("#ebdbb2"),
+	Success: lipgloss.Color("#b8bb26"),
+	Error:   lipgloss.Color("#fb4934"),
+	Info:    lipgloss.Color("#8ec07c"),
+	BgDark:  lipgloss.Color("#3c3836"),
+}
+
+var nord = ThemeColors{
+	Border:  lipgloss.Color("#81a1c1"),
+	Primary: lipgloss.Color("#d08770"),
+	Title:   lipgloss.Color("#b48ead"),
+	Muted:   lipgloss.Color("#616e88"),
+	Text:    lipgloss.Color("#d8dee9"),
+	Success: lipgloss.Color("#a3be8c"),
+	Error:   lipgloss.Color("#bf616a"),
+	Info:    lipgloss.Color("#88c0d0"),
+	BgDark:  lipgloss.Color("#3b4252"),
+}
+
+var monochrome = ThemeColors{
+	Border:     lipgloss.NoColor{},
+	Primary:    lipgloss.NoColor{},
+	Title:      lipgloss.NoColor{},
+	Muted:      lipgloss.NoColor{},
+	Text:       lipgloss.NoColor{},
+	Success:    lipgloss.NoColor{},
+	Error:      lipgloss.NoColor{},
+	Info:       lipgloss.NoColor{},
+	BgDark:     lipgloss.NoColor{},
+	Monochrome: true,
+}
+
+// Presets are the built in themes by name
+var Presets = map[string]ThemeColors{
+	"adaptive":         adaptive(latte, mocha),
+	"catppuccin-mocha": mocha,
+	"catppuccin-latte": latte,
+	"gruvbox-dark":     gruvbox,
+	"nord":             nord,
+	"monochrome":       monochrome,
+}
+
+// adaptive picks between a light and a dark palette based on the terminal background
+func adaptive(light ThemeColors, dark ThemeColors) ThemeColors {
+	pick := func(l lipgloss.TerminalColor, d lipgloss.TerminalColor) lipgloss.TerminalColor {
+		return lipgloss.AdaptiveColor{Light: hex(l), Dark: hex(d)}
+	}
+	return ThemeColors{
+		Border:  pick(light.Border, dark.Border),
+		Primary: pick(light.Primary, dark.Primary),
+		Title:   pick(light.Title, dark.Title),
+		Muted:   pick(light.Muted, dark.Muted),
+		Text:    pick(light.Text, dark.Text),
+		Success: pick(light.Success, dark.Success),
+		Error:   pick(light.Error, dark.Error),
+		Info:    pick(light.Info, dark.Info),
+		BgDark:  pick(light.BgDark, dark.BgDark),
+	}
+}
+
+func hex(c lipgloss.TerminalColor) string {
+	if color, ok := c.(lipgloss.Color); ok {
+		return string(color)
+	}
+	return ""
+}
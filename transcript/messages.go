@@ -0,0 +1,185 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Kind int
+
+const (
+	KindPrompt Kind = iota
+	KindAssistant
+	KindThinking
+	KindToolCall
+	KindToolResult
+)
+
+// Message is one piece of the conversation: a prompt, a reply, or a tool call or its result
+type Message struct {
+	Kind    Kind
+	Time    time.Time
+	Text    string
+	Tool    string // name of the tool of a call
+	Input   string // summary of a tool call's input, like the command or file
+	IsError bool   // a tool result reporting a failure
+}
+
+type line struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	IsMeta    bool      `json:"isMeta"`
+	Message   struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+type block struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text"`
+	Thinking string          `json:"thinking"`
+	Name     string          `json:"name"`
+	Input    json.RawMessage `json:"input"`
+	Content  json.RawMessage `json:"content"`
+	IsError  bool            `json:"is_error"`
+}
+
+// Messages reads the conversation in the transcript at path
+func Messages(path string) ([]Message, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	messages := []Message{}
+	scanner := bufio.NewScanner(file)
+	// Tool results such as whole files make for long lines
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var l line
+		if json.Unmarshal(scanner.Bytes(), &l) != nil || l.IsMeta {
+			continue
+		}
+		switch l.Type {
+		case "user", "assistant":
+			messages = append(messages, parseContent(l)...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript %s: %w", path, err)
+	}
+	return messages, nil
+}
+
+func parseContent(l line) []Message {
+	// Prompts typed by the user are a plain string, everything else a list of blocks
+	var text string
+	if json.Unmarshal(l.Message.Content, &text) == nil {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return []Message{{Kind: KindPrompt, Time: l.Timestamp, Text: text}}
+	}
+
+	var blocks []block
+	if json.Unmarshal(l.Message.Content, &blocks) != nil {
+		return nil
+	}
+
+	messages := []Message{}
+	for _, b := range blocks {
+		m := Message{Time: l.Timestamp}
+		switch b.Type {
+		case "text":
+			if strings.TrimSpace(b.Text) == "" {
+				continue
+			}
+			m.Kind = KindAssistant
+			if l.Type == "user" {
+				m.Kind = KindPrompt
+			}
+			m.Text = b.Text
+		case "thinking":
+			if strings.TrimSpace(b.Thinking) == "" {
+				continue
+			}
+			m.Kind = KindThinking
+			m.Text = b.Thinking
+		case "tool_use":
+			m.Kind = KindToolCall
+			m.Tool = b.Name
+			m.Input = summarizeInput(b.Input)
+			m.Text = prettyJSON(b.Input)
+		case "tool_result":
+			m.Kind = KindToolResult
+			m.Text = resultText(b.Content)
+			m.IsError = b.IsError
+		default:
+			continue
+		}
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+// summarizeInput picks the most telling argument of a tool call for its one line summary
+func summarizeInput(input json.RawMessage) string {
+	var args map[string]any
+	if json.Unmarshal(input, &args) != nil {
+		return ""
+	}
+	for _, key := range []string{"command", "file_path", "path", "pattern", "url", "query", "description", "prompt"} {
+		if value, ok := args[key].(string); ok && value != "" {
+			line, _, _ := strings.Cut(value, "\n")
+			return line
+		}
+	}
+
+	keys := []string{}
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+func prettyJSON(raw json.RawMessage) string {
+	var value any
+	if json.Unmarshal(raw, &value) != nil {
+		return string(raw)
+	}
+	pretty, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return string(raw)
+	}
+	return string(pretty)
+}
+
+// resultText flattens a tool result, which is a string or a list of text blocks
+func resultText(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return text
+	}
+
+	var blocks []block
+	if json.Unmarshal(content, &blocks) != nil {
+		return string(content)
+	}
+	parts := []string{}
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			parts = append(parts, b.Text)
+		default:
+			parts = append(parts, "["+b.Type+"]")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
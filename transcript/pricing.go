@@ -0,0 +1,40 @@
+package transcript
+
+import "strings"
+
+// price is the list price in USD per million tokens
+type price struct {
+	input      float64
+	output     float64
+	cacheWrite float64
+	cacheRead  float64
+}
+
+func (p price) cost(u Usage) float64 {
+	return (float64(u.InputTokens)*p.input +
+		float64(u.OutputTokens)*p.output +
+		float64(u.CacheCreationTokens)*p.cacheWrite +
+		float64(u.CacheReadTokens)*p.cacheRead) / 1_000_000
+}
+
+// prices are matched against the model id in order, the first match wins
+var prices = []struct {
+	model string
+	price price
+}{
+	{"opus-4-5", price{5, 25, 6.25, 0.50}},
+	{"opus", price{15, 75, 18.75, 1.50}},
+	{"sonnet", price{3, 15, 3.75, 0.30}},
+	{"haiku-4-5", price{1, 5, 1.25, 0.10}},
+	{"haiku", price{0.80, 4, 1, 0.08}},
+}
+
+// priceFor returns the price of a model, Sonnet's for models not in the table
+func priceFor(model string) price {
+	for _, p := range prices {
+		if strings.Contains(model, p.model) {
+			return p.price
+		}
+	}
+	return prices[2].price
+}
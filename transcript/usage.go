@@ -0,0 +1,225 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jquag/ai-mux/data"
+	"github.com/jquag/ai-mux/util"
+)
+
+// Usage is the token usage of a Claude session
+type Usage struct {
+	InputTokens         int
+	OutputTokens        int
+	CacheCreationTokens int
+	CacheReadTokens     int
+	Cost                float64 // estimated in USD from list prices
+	Messages            int     // assistant messages counted
+}
+
+// Tokens is the total of every kind of token
+func (u Usage) Tokens() int {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+}
+
+// Add returns the sum of both usages
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:         u.InputTokens + other.InputTokens,
+		OutputTokens:        u.OutputTokens + other.OutputTokens,
+		CacheCreationTokens: u.CacheCreationTokens + other.CacheCreationTokens,
+		CacheReadTokens:     u.CacheReadTokens + other.CacheReadTokens,
+		Cost:                u.Cost + other.Cost,
+		Messages:            u.Messages + other.Messages,
+	}
+}
+
+// entry is the part of a transcript line needed for usage
+type entry struct {
+	Type    string `json:"type"`
+	Message struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// parsed is what has been read of a transcript so far. Claude writes one line per content
+// block of a response, each repeating the usage of the whole message, so usage is kept by
+// message id and the last line for a message wins.
+type parsed struct {
+	offset   int64
+	modTime  time.Time
+	last     []byte // the last line read, still in place unless the transcript was rewritten
+	messages map[string]Usage
+	order    []string
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]*parsed{}
+)
+
+// ForItem returns the usage of the work item's Claude session, zero when it has no transcript
+func ForItem(item *data.WorkItem) (Usage, error) {
+	path := PathFor(item)
+	if path == "" {
+		return Usage{}, nil
+	}
+	usage, err := Read(path)
+	if os.IsNotExist(err) {
+		return Usage{}, nil
+	}
+	return usage, err
+}
+
+// PathFor returns the transcript of the item's session. It is reported by the hooks, and
+// for sessions started before they reported it Claude's default location is tried.
+func PathFor(item *data.WorkItem) string {
+	if path := util.ReadTranscriptPath(item.Id); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil || item.WorktreePath == "" {
+		return ""
+	}
+	worktree, err := filepath.Abs(item.WorktreePath)
+	if err != nil {
+		return ""
+	}
+	// Claude names the project folder after the working directory with every character
+	// other than letters and digits replaced by a dash
+	project := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, worktree)
+	return filepath.Join(home, ".claude", "projects", project, item.Id+".jsonl")
+}
+
+// Read returns the usage in the transcript at path. Results are cached and only lines
+// appended since the last read are parsed.
+func Read(path string) (Usage, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	p, ok := cache[path]
+	if !ok || info.Size() < p.offset {
+		// New or rewritten transcript, start over
+		p = &parsed{messages: map[string]Usage{}}
+		cache[path] = p
+	}
+	if info.Size() != p.offset || !info.ModTime().Equal(p.modTime) {
+		if err := p.readFrom(path); err != nil {
+			return Usage{}, err
+		}
+		p.modTime = info.ModTime()
+	}
+	return p.total(), nil
+}
+
+func (p *parsed) readFrom(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// A rewritten transcript no longer has the last line read where it was, read it anew
+	if len(p.last) > 0 {
+		last := make([]byte, len(p.last))
+		if _, err := file.ReadAt(last, p.offset-int64(len(last))); err != nil || !bytes.Equal(last, p.last) {
+			*p = parsed{messages: map[string]Usage{}}
+		}
+	}
+
+	if _, err := file.Seek(p.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read transcript %s: %w", path, err)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// A partial last line is still being written, read it next time
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read transcript %s: %w", path, err)
+		}
+		p.offset += int64(len(line))
+		p.last = line
+		p.add(bytes.TrimSpace(line))
+	}
+}
+
+func (p *parsed) add(line []byte) {
+	var e entry
+	if len(line) == 0 || json.Unmarshal(line, &e) != nil {
+		return
+	}
+	if e.Type != "assistant" || e.Message.Usage == nil || e.Message.ID == "" {
+		return
+	}
+
+	u := e.Message.Usage
+	usage := Usage{
+		InputTokens:         u.InputTokens,
+		OutputTokens:        u.OutputTokens,
+		CacheCreationTokens: u.CacheCreationInputTokens,
+		CacheReadTokens:     u.CacheReadInputTokens,
+		Messages:            1,
+	}
+	usage.Cost = priceFor(e.Message.Model).cost(usage)
+
+	if _, seen := p.messages[e.Message.ID]; !seen {
+		p.order = append(p.order, e.Message.ID)
+	}
+	p.messages[e.Message.ID] = usage
+}
+
+func (p *parsed) total() Usage {
+	total := Usage{}
+	for _, id := range p.order {
+		total = total.Add(p.messages[id])
+	}
+	return total
+}
+
+// FormatTokens shortens a token count, like 950, 12.3k or 1.2M
+func FormatTokens(tokens int) string {
+	switch {
+	case tokens >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
+	case tokens >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(tokens)/1_000)
+	default:
+		return fmt.Sprintf("%d", tokens)
+	}
+}
+
+// FormatCost formats an estimated cost in USD
+func FormatCost(cost float64) string {
+	return fmt.Sprintf("$%.2f", cost)
+}
@@ -0,0 +1,150 @@
+package transcript
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// assistant is a transcript line of an assistant message with its usage
+func assistant(id string, model string, input int, output int) string {
+	return fmt.Sprintf(`{"type":"assistant","message":{"id":%q,"model":%q,"usage":{"input_tokens":%d,"output_tokens":%d,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}}`+"\n",
+		id, model, input, output)
+}
+
+// write is a change to the transcript file followed by a read
+type write struct {
+	content string
+	append  bool // added to the end instead of replacing the file
+	want    Usage
+}
+
+func TestRead(t *testing.T) {
+	sonnet := func(input, output, messages int) Usage {
+		u := Usage{InputTokens: input, OutputTokens: output, Messages: messages}
+		u.Cost = float64(input*3+output*15) / 1_000_000
+		return u
+	}
+
+	tests := []struct {
+		name   string
+		writes []write
+	}{
+		{
+			name: "messages add up",
+			writes: []write{
+				{content: assistant("a", "claude-sonnet-4", 100, 10) + assistant("b", "claude-sonnet-4", 200, 20), want: sonnet(300, 30, 2)},
+			},
+		},
+		{
+			name: "content blocks of a message count once, the last line wins",
+			writes: []write{
+				{content: assistant("a", "claude-sonnet-4", 100, 10) + assistant("a", "claude-sonnet-4", 100, 40), want: sonnet(100, 40, 1)},
+			},
+		},
+		{
+			name: "appended lines are added",
+			writes: []write{
+				{content: assistant("a", "claude-sonnet-4", 100, 10), want: sonnet(100, 10, 1)},
+				{content: assistant("a", "claude-sonnet-4", 100, 30), append: true, want: sonnet(100, 30, 1)},
+				{content: assistant("b", "claude-sonnet-4", 200, 20), append: true, want: sonnet(300, 50, 2)},
+			},
+		},
+		{
+			name: "partial last line waits for the rest",
+			writes: []write{
+				{content: assistant("a", "claude-sonnet-4", 100, 10) + assistant("b", "claude-sonnet-4", 200, 20)[:30], want: sonnet(100, 10, 1)},
+				{content: assistant("b", "claude-sonnet-4", 200, 20)[30:], append: true, want: sonnet(300, 30, 2)},
+			},
+		},
+		{
+			name: "truncated transcript is read again",
+			writes: []write{
+				{content: assistant("a", "claude-sonnet-4", 100, 10) + assistant("b", "claude-sonnet-4", 200, 20), want: sonnet(300, 30, 2)},
+				{content: assistant("c", "claude-sonnet-4", 500, 50), want: sonnet(500, 50, 1)},
+			},
+		},
+		{
+			name: "rewrite of the same size is read again",
+			writes: []write{
+				{content: assistant("a", "claude-sonnet-4", 100, 10), want: sonnet(100, 10, 1)},
+				{content: assistant("b", "claude-sonnet-4", 900, 90), want: sonnet(900, 90, 1)},
+			},
+		},
+		{
+			name: "longer rewrite is read again",
+			writes: []write{
+				{content: assistant("a", "claude-sonnet-4", 100, 10), want: sonnet(100, 10, 1)},
+				{content: assistant("b", "claude-sonnet-4", 200, 20) + assistant("c", "claude-sonnet-4", 300, 30), want: sonnet(500, 50, 2)},
+			},
+		},
+		{
+			name: "unknown models are priced as Sonnet",
+			writes: []write{
+				{content: assistant("a", "some-new-model", 100, 10), want: sonnet(100, 10, 1)},
+			},
+		},
+		{
+			name: "models are priced by their family",
+			writes: []write{
+				{content: assistant("a", "claude-opus-4-1", 1000, 100) + assistant("b", "claude-opus-4-5-20251101", 1000, 100), want: Usage{
+					InputTokens: 2000, OutputTokens: 200, Messages: 2,
+					Cost: float64(1000*15+100*75+1000*5+100*25) / 1_000_000,
+				}},
+			},
+		},
+		{
+			name: "other lines are skipped",
+			writes: []write{
+				{content: `{"type":"user","message":{"role":"user","content":"hi"}}` + "\n" +
+					"not json\n\n" +
+					`{"type":"assistant","message":{"id":"x","model":"claude-sonnet-4"}}` + "\n" +
+					`{"type":"assistant","message":{"model":"claude-sonnet-4","usage":{"input_tokens":5}}}` + "\n" +
+					assistant("a", "claude-sonnet-4", 100, 10), want: sonnet(100, 10, 1)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.jsonl")
+			modTime := time.Now()
+			for i, w := range tt.writes {
+				flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+				if w.append {
+					flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+				}
+				file, err := os.OpenFile(path, flags, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := file.WriteString(w.content); err != nil {
+					t.Fatal(err)
+				}
+				file.Close()
+				// Every write shows as a change, however coarse the file system's clock
+				modTime = modTime.Add(time.Second)
+				if err := os.Chtimes(path, modTime, modTime); err != nil {
+					t.Fatal(err)
+				}
+
+				got, err := Read(path)
+				if err != nil {
+					t.Fatalf("Read() after write %d failed: %v", i+1, err)
+				}
+				if !sameUsage(got, w.want) {
+					t.Errorf("Read() after write %d = %+v, want %+v", i+1, got, w.want)
+				}
+			}
+		})
+	}
+}
+
+func sameUsage(a, b Usage) bool {
+	costA, costB := a.Cost, b.Cost
+	a.Cost, b.Cost = 0, 0
+	return a == b && math.Abs(costA-costB) < 1e-9
+}
@@ -1,5 +1,12 @@
 package util
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 type ClaudeHookPayload struct {
 	SessionID      string `json:"session_id"`
 	TranscriptPath string `json:"transcript_path"` // Path to conversation JSON
@@ -8,6 +15,39 @@ type ClaudeHookPayload struct {
 }
 
 func HandleClaudeEvent(payload ClaudeHookPayload, aiMuxDir string) error {
+	if payload.TranscriptPath != "" {
+		if err := WriteTranscriptPath(payload.SessionID, payload.TranscriptPath, aiMuxDir); err != nil {
+			return err
+		}
+	}
+
 	// Use the utility function to write status
 	return WriteStatusLog(payload.SessionID, payload.HookEventName, aiMuxDir)
 }
+
+// WriteTranscriptPath records where Claude keeps the session's transcript, skipping the
+// write when it is unchanged since every hook event carries it
+func WriteTranscriptPath(workItemId string, transcriptPath string, aiMuxDir string) error {
+	path := filepath.Join(aiMuxDir, workItemId, "transcript-path.txt")
+	if current, err := os.ReadFile(path); err == nil && string(current) == transcriptPath {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(transcriptPath), 0644); err != nil {
+		return fmt.Errorf("failed to write transcript path: %w", err)
+	}
+	return nil
+}
+
+// ReadTranscriptPath returns the transcript recorded for the work item, empty when no
+// hook event has reported one yet
+func ReadTranscriptPath(workItemId string) string {
+	content, err := os.ReadFile(filepath.Join(AiMuxDir, workItemId, "transcript-path.txt"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
@@ -5,50 +5,56 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
-// CreateWorktree creates a new git worktree with a new or existing branch
-// Returns the worktree path
-func CreateWorktree(branchName string) (string, error) {
-	// Get the current directory name (main folder)
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %w", err)
-	}
-	mainFolderName := filepath.Base(cwd)
-	
-	// Create worktree path in parent directory under worktrees folder
-	worktreesDir := filepath.Join("..", fmt.Sprintf("%s-worktrees", mainFolderName))
-	worktreePath := filepath.Join(worktreesDir, branchName)
-	
+// CreateWorktree checks out the branch in a new worktree. A branch that doesn't exist yet
+// is created from baseBranch, or from HEAD when baseBranch is empty.
+func CreateWorktree(worktreePath string, branchName string, baseBranch string) error {
 	// Ensure the worktrees directory exists
-	absWorktreesDir, err := filepath.Abs(worktreesDir)
+	absWorktreesDir, err := filepath.Abs(filepath.Dir(worktreePath))
 	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path: %w", err)
+		return fmt.Errorf("failed to get absolute path: %w", err)
 	}
 	if err := os.MkdirAll(absWorktreesDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create worktrees directory: %w", err)
+		return fmt.Errorf("failed to create worktrees directory: %w", err)
 	}
 	
-	// Check if branch already exists
-	checkCmd := exec.Command("git", "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branchName))
-	branchExists := checkCmd.Run() == nil
-	
 	var cmd *exec.Cmd
-	if branchExists {
+	if BranchExists(branchName) {
 		// Use existing branch
 		cmd = exec.Command("git", "worktree", "add", worktreePath, branchName)
 	} else {
 		// Create new branch
-		cmd = exec.Command("git", "worktree", "add", worktreePath, "-b", branchName)
+		args := []string{"worktree", "add", worktreePath, "-b", branchName}
+		if baseBranch != "" {
+			args = append(args, baseBranch)
+		}
+		cmd = exec.Command("git", args...)
 	}
 	
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("failed to create worktree: %w - %s", err, string(output))
+		return fmt.Errorf("failed to create worktree: %w - %s", err, string(output))
 	}
 	
-	return worktreePath, nil
+	return nil
+}
+
+// BranchExists checks if a local branch exists
+func BranchExists(branchName string) bool {
+	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", fmt.Sprintf("refs/heads/%s", branchName))
+	return cmd.Run() == nil
+}
+
+// RenameBranch renames a local branch, including when it is checked out in a worktree
+func RenameBranch(oldName string, newName string) error {
+	cmd := exec.Command("git", "branch", "-m", oldName, newName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to rename branch: %w - %s", err, string(output))
+	}
+	return nil
 }
 
 // RemoveWorktree removes a git worktree
@@ -95,3 +101,135 @@ func GetColoredGitDiff(worktreePath string) (string, error) {
 	
 	return string(output), nil
 }
+
+// Worktree describes an entry from `git worktree list`
+type Worktree struct {
+	Path   string
+	Branch string
+	Head   string
+}
+
+// ListLocalBranches returns the short names of all local branches
+func ListLocalBranches() ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	branches := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			branches = append(branches, line)
+		}
+	}
+	return branches, nil
+}
+
+// ListWorktrees returns all worktrees of the current repository, the main worktree first
+func ListWorktrees() ([]Worktree, error) {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	worktrees := []Worktree{}
+	var current *Worktree
+	for _, line := range strings.Split(string(output), "\n") {
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			worktrees = append(worktrees, Worktree{Path: strings.TrimPrefix(line, "worktree ")})
+			current = &worktrees[len(worktrees)-1]
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "HEAD "):
+			current.Head = strings.TrimPrefix(line, "HEAD ")
+		case strings.HasPrefix(line, "branch "):
+			current.Branch = strings.TrimPrefix(strings.TrimPrefix(line, "branch "), "refs/heads/")
+		}
+	}
+	return worktrees, nil
+}
+
+// DefaultWorktreePath returns the path a worktree named name gets when ai-mux creates it
+func DefaultWorktreePath(name string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	mainFolderName := filepath.Base(cwd)
+	return filepath.Join("..", fmt.Sprintf("%s-worktrees", mainFolderName), name), nil
+}
+
+// UncommittedFiles lists the files with uncommitted changes in a worktree
+func UncommittedFiles(worktreePath string) ([]string, error) {
+	cmd := exec.Command("git", "-C", worktreePath, "status", "--porcelain")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check worktree status: %w - %s", err, string(output))
+	}
+	return nonEmptyLines(string(output)), nil
+}
+
+// UnpushedCommits lists the commits of a worktree's branch missing from its upstream, or from
+// baseBranch when the branch has no upstream
+func UnpushedCommits(worktreePath string, baseBranch string) ([]string, error) {
+	cmd := exec.Command("git", "-C", worktreePath, "log", "--oneline", "@{u}..HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		cmd = exec.Command("git", "-C", worktreePath, "log", "--oneline", baseBranch+"..HEAD")
+		output, err = cmd.CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list unpushed commits: %w - %s", err, string(output))
+		}
+	}
+	return nonEmptyLines(string(output)), nil
+}
+
+// CurrentBranch returns the branch checked out in the current directory
+func CurrentBranch() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsBranchMerged reports whether the branch has commits of its own and all of them are in
+// the into branch. A branch with no commits since it was created is not merged.
+func IsBranchMerged(branch string, into string) (bool, error) {
+	tip, err := exec.Command("git", "rev-parse", "--verify", branch).Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve branch %s: %w", branch, err)
+	}
+
+	// The oldest reflog entry is the commit the branch was created at
+	reflog, err := exec.Command("git", "rev-list", "--walk-reflogs", branch).Output()
+	if err == nil {
+		entries := nonEmptyLines(string(reflog))
+		if len(entries) > 0 && entries[len(entries)-1] == strings.TrimSpace(string(tip)) {
+			return false, nil
+		}
+	}
+
+	err = exec.Command("git", "merge-base", "--is-ancestor", strings.TrimSpace(string(tip)), into).Run()
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check if %s is merged: %w", branch, err)
+}
+
+func nonEmptyLines(s string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
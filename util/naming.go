@@ -0,0 +1,56 @@
+package util
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"unicode"
+)
+
+// maxSafeNameLength keeps branch and window names readable in the tmux status line
+const maxSafeNameLength = 60
+
+// ToSafeName converts a short name to be safe for tmux window names, git branch names
+// and worktree folders. Letters and digits (including non-ASCII ones) are kept, every
+// other run of characters becomes a single dash. Dots, colons and slashes are replaced
+// since tmux treats them as target separators and git restricts them in ref names.
+func ToSafeName(shortName string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range shortName {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			if pendingDash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			pendingDash = false
+			b.WriteRune(r)
+		} else {
+			pendingDash = true
+		}
+	}
+
+	name := []rune(b.String())
+	if len(name) > maxSafeNameLength {
+		name = name[:maxSafeNameLength]
+	}
+	safe := strings.TrimRight(string(name), "-")
+	if safe == "" {
+		return "item"
+	}
+	return safe
+}
+
+// UniqueName returns base, or base with a numeric suffix, such that taken reports false for it
+func UniqueName(base string, taken func(string) bool) string {
+	name := base
+	for i := 2; taken(name); i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	return name
+}
+
+// IsValidBranchName checks a name against git's rules for branch names
+func IsValidBranchName(name string) bool {
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	return cmd.Run() == nil
+}
@@ -0,0 +1,181 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToSafeName(t *testing.T) {
+	tests := []struct {
+		name      string
+		shortName string
+		want      string
+	}{
+		{
+			name:      "spaces",
+			shortName: "fix login bug",
+			want:      "fix-login-bug",
+		},
+		{
+			name:      "slashes become dashes",
+			shortName: "feature/login",
+			want:      "feature-login",
+		},
+		{
+			name:      "dots and colons become dashes",
+			shortName: "v1.2: release",
+			want:      "v1-2-release",
+		},
+		{
+			name:      "runs of separators collapse",
+			shortName: "a  --//  b",
+			want:      "a-b",
+		},
+		{
+			name:      "leading and trailing separators dropped",
+			shortName: "  -fix-  ",
+			want:      "fix",
+		},
+		{
+			name:      "underscores kept",
+			shortName: "snake_case name",
+			want:      "snake_case-name",
+		},
+		{
+			name:      "non-ASCII letters kept",
+			shortName: "café über 日本",
+			want:      "café-über-日本",
+		},
+		{
+			name:      "git ref syntax removed",
+			shortName: "a..b~1^2@{x}",
+			want:      "a-b-1-2-x",
+		},
+		{
+			name:      "nothing usable",
+			shortName: "../:?*",
+			want:      "item",
+		},
+		{
+			name:      "empty",
+			shortName: "",
+			want:      "item",
+		},
+		{
+			name:      "long names are cut",
+			shortName: strings.Repeat("a", 70),
+			want:      strings.Repeat("a", maxSafeNameLength),
+		},
+		{
+			name:      "cut does not leave a trailing dash",
+			shortName: strings.Repeat("a", maxSafeNameLength-1) + " b",
+			want:      strings.Repeat("a", maxSafeNameLength-1),
+		},
+		{
+			name:      "long non-ASCII names are cut by character",
+			shortName: strings.Repeat("é", 70),
+			want:      strings.Repeat("é", maxSafeNameLength),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToSafeName(tt.shortName)
+			if got != tt.want {
+				t.Errorf("ToSafeName(%q) = %q, want %q", tt.shortName, got, tt.want)
+			}
+			if !IsValidBranchName(got) {
+				t.Errorf("ToSafeName(%q) = %q, which is not a valid branch name", tt.shortName, got)
+			}
+		})
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  string
+		taken []string
+		want  string
+	}{
+		{
+			name: "free",
+			base: "fix",
+			want: "fix",
+		},
+		{
+			name:  "taken",
+			base:  "fix",
+			taken: []string{"fix"},
+			want:  "fix-2",
+		},
+		{
+			name:  "suffixes taken too",
+			base:  "fix",
+			taken: []string{"fix", "fix-2", "fix-3"},
+			want:  "fix-4",
+		},
+		{
+			name:  "gap in suffixes is used",
+			base:  "fix",
+			taken: []string{"fix", "fix-3"},
+			want:  "fix-2",
+		},
+		{
+			name:  "other names don't matter",
+			base:  "fix",
+			taken: []string{"fix-2", "fixes"},
+			want:  "fix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taken := func(name string) bool {
+				for _, n := range tt.taken {
+					if n == name {
+						return true
+					}
+				}
+				return false
+			}
+			got := UniqueName(tt.base, taken)
+			if got != tt.want {
+				t.Errorf("UniqueName(%q) with %v taken = %q, want %q", tt.base, tt.taken, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidBranchName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"fix-login", true},
+		{"feature/login", true},
+		{"café-日本", true},
+		{"", false},
+		{"-fix", false},
+		{"a..b", false},
+		{"a b", false},
+		{"a:b", false},
+		{"a~b", false},
+		{"a^b", false},
+		{"a?b", false},
+		{"a*b", false},
+		{"a[b", false},
+		{"a@{b", false},
+		{"fix.lock", false},
+		{"fix/", false},
+		{".fix", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidBranchName(tt.name); got != tt.want {
+				t.Errorf("IsValidBranchName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
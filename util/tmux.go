@@ -4,14 +4,75 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 )
 
+// paneWatchInterval is how often WaitForPane looks at the pane
+const paneWatchInterval = 250 * time.Millisecond
+
 func InTmuxSession() bool {
 	_, exists := os.LookupEnv("TMUX")
 	return exists
 }
 
+// controlDirOption is the session option holding the repository a control session is for
+const controlDirOption = "@ai-mux-dir"
+
+// ControlSessionName names the tmux session of the repository in dir, the session ai-mux is
+// launched into and work item windows live in. Each repository gets its own.
+func ControlSessionName(dir string) string {
+	return "ai-mux-" + ToSafeName(filepath.Base(dir))
+}
+
+// TmuxSessionName returns the session work item windows live in, the control session of the
+// repository in the working directory, whether or not ai-mux runs inside it
+func TmuxSessionName() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "ai-mux"
+	}
+	return ControlSessionName(dir)
+}
+
+// SessionDir returns the repository a control session is for, empty when the session
+// wasn't made by ai-mux
+func SessionDir(sessionName string) string {
+	output, _ := exec.Command("tmux", "show-options", "-v", "-t", sessionName, controlDirOption).Output()
+	return strings.TrimSpace(string(output))
+}
+
+// SetSessionDir records the repository a control session is for
+func SetSessionDir(sessionName string, dir string) error {
+	if err := runTmux("set-option", "-t", sessionName, controlDirOption, dir); err != nil {
+		return fmt.Errorf("failed to mark tmux session '%s': %w", sessionName, err)
+	}
+	return nil
+}
+
+// CheckSessionDir returns an error when the control session belongs to another repository,
+// repositories of the same folder name share the session name
+func CheckSessionDir(sessionName string, dir string) error {
+	switch owner := SessionDir(sessionName); owner {
+	case dir:
+		return nil
+	case "":
+		return fmt.Errorf("tmux session '%s' was not made by ai-mux, rename or close it to run ai-mux for %s", sessionName, dir)
+	default:
+		return fmt.Errorf("tmux session '%s' runs ai-mux for %s, rename or close it to run ai-mux for %s", sessionName, owner, dir)
+	}
+}
+
+// WindowTarget addresses a window in tmux commands, in the current session when sessionName is empty
+func WindowTarget(windowName string, sessionName string) string {
+	if sessionName == "" {
+		return windowName
+	}
+	return sessionName + ":" + windowName
+}
+
 // EnsureTmuxSession creates a tmux session if it doesn't exist
 func EnsureTmuxSession(sessionName string) (bool, error) {
 	// Check if session exists
@@ -32,14 +93,23 @@ func EnsureTmuxSession(sessionName string) (bool, error) {
 
 // WindowExists checks if a tmux window exists
 func WindowExists(windowName string, sessionName string) bool {
-	target := windowName
+	args := []string{"list-windows", "-F", "#{window_name}"}
 	if sessionName != "" {
-		target = sessionName + ":" + windowName
+		args = append(args, "-t", sessionName)
 	}
 	
-	cmd := exec.Command("tmux", "list-windows", "-t", target, "-F", "#{window_name}")
-	err := cmd.Run()
-	return err == nil
+	cmd := exec.Command("tmux", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	
+	for _, name := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if name == windowName {
+			return true
+		}
+	}
+	return false
 }
 
 // CreateTmuxWindow creates a new tmux window in the specified session or current session
@@ -55,51 +125,82 @@ func CreateTmuxWindow(windowName string, sessionName string, workingDir string)
 		args = append(args, "-c", workingDir)
 	}
 	
-	cmd := exec.Command("tmux", args...)
-	return cmd.Run()
+	return runTmux(args...)
 }
 
-// RunCommandInTmuxWindow runs a command in a specific tmux window
-func RunCommandInTmuxWindow(windowName string, sessionName string, command string) error {
-	target := windowName
-	if sessionName != "" {
-		target = sessionName + ":" + windowName
+// SendTextToTmuxPane types text into a pane as it is, so words like Enter or C-c are not
+// read as key names. Text of several lines is pasted in one go with bracketed paste so its
+// line breaks don't submit it early. The pane is a pane id or a window target.
+func SendTextToTmuxPane(paneId string, text string) error {
+	if !strings.Contains(text, "\n") {
+		if err := runTmux("send-keys", "-l", "-t", paneId, "--", text); err != nil {
+			return fmt.Errorf("failed to send text to pane '%s': %w", paneId, err)
+		}
+		return nil
+	}
+
+	buffer := fmt.Sprintf("ai-mux-%d", time.Now().UnixNano())
+	load := exec.Command("tmux", "load-buffer", "-b", buffer, "-")
+	load.Stdin = strings.NewReader(text)
+	if output, err := load.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to load text for pane '%s': %w - output: %s", paneId, err, string(output))
+	}
+	// -r keeps line feeds instead of turning them into carriage returns, -d drops the buffer
+	if err := runTmux("paste-buffer", "-p", "-r", "-d", "-b", buffer, "-t", paneId); err != nil {
+		return fmt.Errorf("failed to paste text to pane '%s': %w", paneId, err)
 	}
-	
-	// Send the command to the tmux window
-	cmd := exec.Command("tmux", "send-keys", "-t", target, command)
-	cmd.Run()
-	cmd = exec.Command("tmux", "send-keys", "-t", target, "Enter")
-	cmd.Run()
 	return nil
 }
 
-// RunCommandInTmuxPane runs a command in a specific tmux pane by pane ID
-func RunCommandInTmuxPane(paneId string, command string) error {
-	// Send the command to the tmux pane
-	cmd := exec.Command("tmux", "send-keys", "-t", paneId, command)
-	cmd.Run()
-	cmd = exec.Command("tmux", "send-keys", "-t", paneId, "Enter")
-	cmd.Run()
+// SubmitTextToTmuxPane types text into a pane and presses Enter, like running a command
+// in a shell or sending a prompt to Claude
+func SubmitTextToTmuxPane(paneId string, text string) error {
+	if err := SendTextToTmuxPane(paneId, text); err != nil {
+		return err
+	}
+	return SendKeysToTmuxPane(paneId, "Enter")
+}
+
+// SendKeysToTmuxPane sends key names such as Enter or C-c to a pane
+func SendKeysToTmuxPane(paneId string, keys ...string) error {
+	args := append([]string{"send-keys", "-t", paneId}, keys...)
+	cmd := exec.Command("tmux", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to send keys to pane '%s': %w - output: %s", paneId, err, string(output))
+	}
 	return nil
 }
 
-// SplitTmuxWindow creates a vertical split in the specified window
-func SplitTmuxWindow(windowName string, sessionName string, folder string) error {
+// SplitTmuxWindow splits the specified window, adding a pane below or, side by side, to the right
+func SplitTmuxWindow(windowName string, sessionName string, folder string, sideBySide bool) error {
 	target := windowName
 	if sessionName != "" {
 		target = sessionName + ":" + windowName
 	}
 	
-	// Create vertical split
-	cmd := exec.Command("tmux", "split-window", "-v", "-t", target, "-c", folder)
-	return cmd.Run()
+	direction := "-v"
+	if sideBySide {
+		direction = "-h"
+	}
+	return runTmux("split-window", direction, "-t", target, "-c", folder)
 }
 
 // SetPaneVariable sets a custom variable on a specific pane
 func SetPaneVariable(paneId string, variable string, value string) error {
-	cmd := exec.Command("tmux", "set", "-p", "-t", paneId, "@"+variable, value)
-	return cmd.Run()
+	if err := runTmux("set", "-p", "-t", paneId, "@"+variable, value); err != nil {
+		return fmt.Errorf("failed to set @%s on pane '%s': %w", variable, paneId, err)
+	}
+	return nil
+}
+
+// runTmux runs a tmux command, its output becoming part of the error when it fails
+func runTmux(args ...string) error {
+	output, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w - output: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
 }
 
 // FindPaneByVariable finds a pane by searching for a custom variable value in a specific window
@@ -129,15 +230,19 @@ func FindPaneByVariable(windowName string, sessionName string, variable string,
 	return "", fmt.Errorf("no pane found with %s=%s in window %s", variable, value, target)
 }
 
-// SwitchToTmuxWindow switches focus to a specific tmux window
+// SwitchToTmuxWindow shows a specific tmux window. Inside tmux the client moves over to it,
+// whatever session it shows. Outside tmux the window is made current for the clients attached
+// to its session, it fails when there are none since nothing would show it.
 func SwitchToTmuxWindow(windowName string, sessionName string) error {
-	target := windowName
-	if sessionName != "" {
-		target = sessionName + ":" + windowName
+	target := WindowTarget(windowName, sessionName)
+
+	cmd := exec.Command("tmux", "switch-client", "-t", target)
+	if !InTmuxSession() {
+		if sessionName != "" && !hasClients(sessionName) {
+			return fmt.Errorf("no terminal is attached to tmux session '%s', run 'tmux attach -t %s' to see window '%s'", sessionName, sessionName, windowName)
+		}
+		cmd = exec.Command("tmux", "select-window", "-t", target)
 	}
-	
-	// Switch to the tmux window
-	cmd := exec.Command("tmux", "select-window", "-t", target)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to switch to window '%s': %w - output: %s", target, err, string(output))
@@ -145,6 +250,12 @@ func SwitchToTmuxWindow(windowName string, sessionName string) error {
 	return nil
 }
 
+// hasClients reports whether a terminal is attached to the session
+func hasClients(sessionName string) bool {
+	output, err := exec.Command("tmux", "list-clients", "-t", sessionName, "-F", "#{client_name}").Output()
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
+
 // KillTmuxWindow kills a specific tmux window
 func KillTmuxWindow(windowName string, sessionName string) error {
 	target := windowName
@@ -153,6 +264,72 @@ func KillTmuxWindow(windowName string, sessionName string) error {
 	}
 	
 	// Kill the tmux window
-	cmd := exec.Command("tmux", "kill-window", "-t", target)
-	return cmd.Run()
+	return runTmux("kill-window", "-t", target)
+}
+
+// RenameTmuxWindow renames a specific tmux window
+func RenameTmuxWindow(windowName string, sessionName string, newName string) error {
+	target := windowName
+	if sessionName != "" {
+		target = sessionName + ":" + windowName
+	}
+	
+	cmd := exec.Command("tmux", "rename-window", "-t", target, newName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to rename window '%s': %w - output: %s", target, err, string(output))
+	}
+	return nil
+}
+
+// CapturePane returns the text currently shown in a pane, wrapped lines joined
+func CapturePane(paneId string) (string, error) {
+	cmd := exec.Command("tmux", "capture-pane", "-p", "-J", "-t", paneId)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane '%s': %w", paneId, err)
+	}
+	return string(output), nil
+}
+
+// WaitForPane polls what a pane shows until one of the patterns matches it and returns the
+// index of that pattern. The command typed into the pane is left out so its arguments don't
+// match. It gives up after timeout, reporting the last line on screen.
+func WaitForPane(paneId string, patterns []*regexp.Regexp, command string, timeout time.Duration) (int, error) {
+	deadline := time.Now().Add(timeout)
+	screen := ""
+	for {
+		var err error
+		screen, err = CapturePane(paneId)
+		if err != nil {
+			return -1, err
+		}
+		if match := matchScreen(screen, patterns, command); match >= 0 {
+			return match, nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(paneWatchInterval)
+	}
+
+	lastLine := ""
+	if lines := nonEmptyLines(screen); len(lines) > 0 {
+		lastLine = strings.TrimSpace(lines[len(lines)-1])
+	}
+	return -1, fmt.Errorf("timed out after %s, the pane shows %q", timeout, lastLine)
+}
+
+// matchScreen returns the index of the first pattern matching screen once command is
+// removed from it, or -1 when none does
+func matchScreen(screen string, patterns []*regexp.Regexp, command string) int {
+	if command != "" {
+		screen = strings.ReplaceAll(screen, command, "")
+	}
+	for i, pattern := range patterns {
+		if pattern.MatchString(screen) {
+			return i
+		}
+	}
+	return -1
 }
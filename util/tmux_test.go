@@ -0,0 +1,70 @@
+package util
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestMatchScreen(t *testing.T) {
+	ready := regexp.MustCompile(`(?i)\? for shortcuts|plan mode on`)
+	trust := regexp.MustCompile(`(?i)do you trust the files in this folder`)
+	patterns := []*regexp.Regexp{ready, trust}
+	command := "claude --session-id id-1 --permission-mode plan 'turn plan mode on for the ? for shortcuts hint'"
+
+	tests := []struct {
+		name    string
+		screen  string
+		command string
+		want    int
+	}{
+		{
+			name:    "nothing yet",
+			screen:  "$ " + command + "\n",
+			command: command,
+			want:    -1,
+		},
+		{
+			name:    "ready",
+			screen:  "$ " + command + "\n\n> \n  ? for shortcuts\n",
+			command: command,
+			want:    0,
+		},
+		{
+			name:    "second pattern",
+			screen:  "$ " + command + "\n Do you trust the files in this folder?\n ❯ 1. Yes, proceed\n",
+			command: command,
+			want:    1,
+		},
+		{
+			name:    "first pattern wins when both match",
+			screen:  "Do you trust the files in this folder?\n⏸ plan mode on\n",
+			command: command,
+			want:    0,
+		},
+		{
+			name:    "command shown more than once",
+			screen:  "$ " + command + "\nzsh: command not found: claude\n$ " + command + "\n",
+			command: command,
+			want:    -1,
+		},
+		{
+			name:   "without a command the whole screen counts",
+			screen: "$ " + command + "\n",
+			want:   0,
+		},
+		{
+			name:    "empty screen",
+			screen:  "",
+			command: command,
+			want:    -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchScreen(tt.screen, patterns, tt.command); got != tt.want {
+				t.Errorf("matchScreen(%q) = %d, want %d", tt.screen, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,12 +6,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jquag/ai-mux/data"
 )
 
-func TitledBorderStyle(color lipgloss.Color, title string, width int) lipgloss.Style {
+func TitledBorderStyle(color lipgloss.TerminalColor, title string, width int) lipgloss.Style {
 	style := lipgloss.NewStyle()
 	if width <= 0 {
 		return style
@@ -57,12 +58,6 @@ func ShellQuote(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
 }
 
-// ToSafeName converts a short name to be safe for tmux window names and git branch names
-// by replacing spaces with dashes
-func ToSafeName(shortName string) string {
-	return strings.ReplaceAll(shortName, " ", "-")
-}
-
 // WriteStatusLog writes a status to the work item's status log
 func WriteStatusLog(workItemId string, status string, aiMuxDir string) error {
 	statusLogPath := filepath.Join(aiMuxDir, workItemId, "state-log.txt")
@@ -80,14 +75,53 @@ func WriteStatusLog(workItemId string, status string, aiMuxDir string) error {
 	}
 	defer file.Close()
 	
-	// Write a newline first, then the status
-	if _, err := file.WriteString("\n" + status); err != nil {
+	// Write a newline first, then the status and when it was reached
+	if _, err := file.WriteString("\n" + status + "\t" + time.Now().UTC().Format(time.RFC3339)); err != nil {
 		return fmt.Errorf("failed to write to status log: %w", err)
 	}
 	
 	return nil
 }
 
+// ReadStatusLog returns the entries of the work item's status log, oldest first. Lines are
+// "status<TAB>RFC3339 time", older logs have only the status.
+func ReadStatusLog(workItemId string) ([]data.StatusEntry, error) {
+	content, err := os.ReadFile(filepath.Join(AiMuxDir, workItemId, "state-log.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []data.StatusEntry{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		status, stamp, _ := strings.Cut(line, "\t")
+		entry := data.StatusEntry{Status: status}
+		if t, err := time.Parse(time.RFC3339, stamp); err == nil {
+			entry.Time = t
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// FormatDuration shortens a duration to its two largest units, like 45s, 12m, 1h 3m or 2d 4h
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
 // UpdateWorkItem updates an existing work item's JSON file
 func UpdateWorkItem(item *data.WorkItem) error {
 	// Update the item.json file